@@ -0,0 +1,122 @@
+package api_handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// setupAtomicRoutes adds the routes for counters and conditional writes,
+// which let clients update a key without racing a GET against a POST.
+func setupAtomicRoutes(router *gin.Engine, store metrics.Instrumented) {
+	incr := func(sign int64) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			var data CounterRequest
+			if err := c.ShouldBindJSON(&data); err != nil || data.Key == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+				return
+			}
+			delta := int64(1)
+			if data.Delta != nil {
+				delta = *data.Delta
+			}
+
+			value, err := store.IncrBy(c.Request.Context(), data.Key, sign*delta)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"key": data.Key, "value": value})
+		}
+	}
+	router.POST("/cache/incr", incr(1))
+	router.POST("/cache/decr", incr(-1))
+
+	router.POST("/cache/add", func(c *gin.Context) {
+		data, ttl, ok := bindEntry(c)
+		if !ok {
+			return
+		}
+
+		stored, err := store.SetNX(c.Request.Context(), data.Key, data.Value, ttl)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !stored {
+			c.JSON(http.StatusConflict, gin.H{"error": "Key already exists"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Key added successfully"})
+	})
+
+	router.PUT("/cache", func(c *gin.Context) {
+		data, ttl, ok := bindEntry(c)
+		if !ok {
+			return
+		}
+
+		stored, err := store.Replace(c.Request.Context(), data.Key, data.Value, ttl)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !stored {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Key replaced successfully"})
+	})
+
+	router.GET("/cache/versioned", func(c *gin.Context) {
+		key := c.Query("key")
+		if key == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
+			return
+		}
+
+		value, version, err := store.GetVersioned(c.Request.Context(), key)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"key": key, "value": value, "version": version})
+	})
+
+	router.POST("/cache/cas", func(c *gin.Context) {
+		var data CASEntry
+		if err := c.ShouldBindJSON(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		ttl, err := parseTTL(data.TTL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TTL format"})
+			return
+		}
+
+		version, err := store.CompareAndSwap(c.Request.Context(), data.Key, data.Value, data.Version, ttl)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"key": data.Key, "version": version})
+	})
+}
+
+// bindEntry reads a CacheEntry and its TTL, answering 400 if either is invalid.
+func bindEntry(c *gin.Context) (CacheEntry, time.Duration, bool) {
+	var data CacheEntry
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return data, 0, false
+	}
+	ttl, err := parseTTL(data.TTL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TTL format"})
+		return data, 0, false
+	}
+	return data, ttl, true
+}
@@ -0,0 +1,125 @@
+package api_handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBatchSize is the most keys or entries one batch request may carry.
+const MaxBatchSize = 1000
+
+// setupBatchRoutes adds the /cache/batch routes, which read, write and
+// delete many keys in one request and report each key's outcome.
+func setupBatchRoutes(router *gin.Engine, store cache.Cache) {
+	router.POST("/cache/batch/get", func(c *gin.Context) {
+		keys, ok := bindBatchKeys(c)
+		if !ok {
+			return
+		}
+
+		values, err := cache.GetMulti(c.Request.Context(), store, keys)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		results := make([]GetResult, len(keys))
+		for i, key := range keys {
+			value, found := values[key]
+			results[i] = GetResult{Key: key, Found: found, Value: value}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": results})
+	})
+
+	router.POST("/cache/batch", func(c *gin.Context) {
+		var data BatchEntries
+		if err := c.ShouldBindJSON(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if len(data.Entries) == 0 || len(data.Entries) > MaxBatchSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch must hold between 1 and %d entries", MaxBatchSize)})
+			return
+		}
+
+		// Entries sharing a TTL are written in one batch.
+		groups := make(map[time.Duration]map[string]interface{})
+		for _, entry := range data.Entries {
+			ttl, err := parseTTL(entry.TTL)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TTL format for key " + entry.Key})
+				return
+			}
+			if groups[ttl] == nil {
+				groups[ttl] = make(map[string]interface{})
+			}
+			groups[ttl][entry.Key] = entry.Value
+		}
+
+		failed := make(map[string]error)
+		for ttl, items := range groups {
+			err := cache.SetMulti(c.Request.Context(), store, items, ttl)
+			if err == nil {
+				continue
+			}
+			if len(groups) == 1 {
+				respondError(c, err)
+				return
+			}
+			for key := range items {
+				failed[key] = err
+			}
+		}
+
+		results := make([]SetResult, len(data.Entries))
+		status := http.StatusCreated
+		for i, entry := range data.Entries {
+			results[i] = SetResult{Key: entry.Key, Stored: true}
+			if err, ok := failed[entry.Key]; ok {
+				results[i] = SetResult{Key: entry.Key, Error: err.Error()}
+				status = http.StatusMultiStatus
+			}
+		}
+		c.JSON(status, gin.H{"results": results})
+	})
+
+	router.POST("/cache/batch/delete", func(c *gin.Context) {
+		keys, ok := bindBatchKeys(c)
+		if !ok {
+			return
+		}
+
+		deleted, err := cache.DeleteMulti(c.Request.Context(), store, keys)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		wasDeleted := make(map[string]bool, len(deleted))
+		for _, key := range deleted {
+			wasDeleted[key] = true
+		}
+		results := make([]DeleteResult, len(keys))
+		for i, key := range keys {
+			results[i] = DeleteResult{Key: key, Deleted: wasDeleted[key]}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": results})
+	})
+}
+
+// bindBatchKeys reads the keys of a batch get or delete, answering 400 if
+// the body is invalid.
+func bindBatchKeys(c *gin.Context) ([]string, bool) {
+	var data BatchKeys
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return nil, false
+	}
+	if len(data.Keys) == 0 || len(data.Keys) > MaxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch must hold between 1 and %d keys", MaxBatchSize)})
+		return nil, false
+	}
+	return data.Keys, true
+}
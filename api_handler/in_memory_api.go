@@ -1,73 +1,46 @@
 package api_handler
 
 import (
-	"net/http"
+	"log"
 	"time"
 
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/config"
 	"github.com/Devisree146/Go_project-library.git/in_memory"
 	"github.com/gin-gonic/gin"
 )
 
-const TTL = 5 * time.Minute
-
-func SetupInMemoryRouter() *gin.Engine {
-	cache := in_memory.NewInMemoryCache(3, TTL)
-	router := gin.Default()
-
-	router.POST("/cache", func(c *gin.Context) {
-		var data CacheEntry
-		if err := c.ShouldBindJSON(&data); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-			return
-		}
-
-		if err := cache.Set(data.Key, data.Value); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusCreated, gin.H{"message": "Key set successfully"})
-	})
-
-	router.GET("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
-		}
-
-		value, err := cache.Get(key)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"key": key, "value": value})
-	})
-
-	router.DELETE("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
-		}
-
-		if err := cache.Delete(key); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"message": "Key deleted successfully"})
-	})
-
-	router.DELETE("/cache/all", func(c *gin.Context) {
-		cache.DeleteAll()
-		c.JSON(http.StatusOK, gin.H{"message": "All keys deleted successfully"})
-	})
+// SetupInMemoryRouter serves an in-memory cache. The caller must Close the
+// returned cache once the router has stopped serving.
+//
+// If cfg.SnapshotPath is set, the cache starts from the snapshot saved
+// there and is saved back every cfg.SnapshotInterval and when closed. If
+// cfg.LogPath is set, every change is also logged there and replayed at
+// startup.
+func SetupInMemoryRouter(cfg config.Config) (*gin.Engine, cache.Cache) {
+	cacheCfg := inMemoryConfig(cfg)
+	cacheCfg.SnapshotPath = cfg.SnapshotPath
+	cacheCfg.SnapshotInterval = time.Duration(cfg.SnapshotInterval)
+	cacheCfg.LogPath = cfg.LogPath
+	cacheCfg.LogSync = logSyncs[cfg.LogSync]
+	cacheCfg.OnSnapshotError = func(err error) { log.Printf("in_memory snapshot: %v", err) }
+	backend := in_memory.NewBackend(in_memory.NewWithConfig(cacheCfg))
+	return SetupNamedRouter("in_memory", backend), backend
+}
 
-	router.GET("/cache/all", func(c *gin.Context) {
-		cachedKeys := cache.GetAllKeys()
-		c.JSON(http.StatusOK, gin.H{"keys": cachedKeys})
-	})
+// logSyncs maps the config's log sync names to in_memory policies.
+var logSyncs = map[string]in_memory.LogSync{
+	"always":   in_memory.SyncAlways,
+	"everysec": in_memory.SyncEverySecond,
+	"no":       in_memory.SyncNever,
+}
 
-	return router
+// inMemoryConfig returns the settings of the in-memory caches described by cfg.
+func inMemoryConfig(cfg config.Config) in_memory.Config[string, interface{}] {
+	return in_memory.Config[string, interface{}]{
+		MaxSize:         cfg.Size,
+		MaxBytes:        cfg.MaxBytes,
+		TTL:             time.Duration(cfg.TTL),
+		JanitorInterval: time.Duration(cfg.JanitorInterval),
+	}
 }
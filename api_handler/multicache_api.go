@@ -1,11 +1,23 @@
 package api_handler
 
 import (
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/config"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
 	"github.com/Devisree146/Go_project-library.git/multicache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupMultiCacheRouter() *gin.Engine {
-	router := multicache.SetupRouter()
-	return router
+// SetupMultiCacheRouter serves an in-memory cache in front of Redis. The
+// caller must Close the returned cache once the router has stopped serving.
+// It fails if the TLS certificates in cfg cannot be loaded.
+func SetupMultiCacheRouter(cfg config.Config) (*gin.Engine, cache.Cache, error) {
+	cacheRedis, err := newRedisCache(cfg, "multi:")
+	if err != nil {
+		return nil, nil, err
+	}
+	cacheInMemory := in_memory.NewWithConfig(inMemoryConfig(cfg))
+	backend := multicache.New(in_memory.NewBackend(cacheInMemory), redis_cache.NewBackend(cacheRedis))
+	return SetupNamedRouter("multicache", backend), backend, nil
 }
@@ -1,92 +1,53 @@
 package api_handler
 
 import (
-	"net/http"
 	"time"
 
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/config"
 	"github.com/Devisree146/Go_project-library.git/redis_cache"
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	standardTTL = 5 * time.Minute // Standard TTL of 5 minutes
-)
-
-func SetupRedisCacheRouter() *gin.Engine {
-	// Initialize your Redis cache instance with maxSize of 3
-	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
-
-	// Setup Gin router
-	router := gin.Default()
-
-	// POST endpoint to set cache with standard TTL
-	router.POST("/cache", func(c *gin.Context) {
-		var data CacheEntry
-		if err := c.ShouldBindJSON(&data); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-			return
-		}
-
-		err := cache.Set(data.Key, data.Value, standardTTL)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set key"})
-			return
-		}
-
-		c.JSON(http.StatusCreated, gin.H{"message": "Key set successfully"})
-	})
-
-	// GET endpoint to retrieve cache
-	router.GET("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
-		}
-
-		value, err := cache.Get(key)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"key": key, "value": value})
-	})
-
-	// DELETE endpoint to delete cache by key
-	router.DELETE("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
-		}
-
-		err := cache.Delete(key)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"message": "Key deleted successfully"})
-	})
-
-	// DELETE endpoint to delete all cache keys
-	router.DELETE("/cache/all", func(c *gin.Context) {
-		cache.DeleteAll()
-
-		c.JSON(http.StatusOK, gin.H{"message": "All keys deleted successfully"})
-	})
-
-	// GET endpoint to retrieve all cache keys
-	router.GET("/cache/all", func(c *gin.Context) {
-		cachedKeys, err := cache.GetAllKeys()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keys"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"keys": cachedKeys})
-	})
+// SetupRedisCacheRouter serves a Redis cache. The caller must Close the
+// returned cache once the router has stopped serving. It fails if the TLS
+// certificates in cfg cannot be loaded.
+func SetupRedisCacheRouter(cfg config.Config) (*gin.Engine, cache.Cache, error) {
+	cacheRedis, err := newRedisCache(cfg, "redis:")
+	if err != nil {
+		return nil, nil, err
+	}
+	backend := redis_cache.NewBackend(cacheRedis)
+	return SetupNamedRouter("redis", backend), backend, nil
+}
 
-	return router
+// newRedisCache connects to the Redis server, Sentinel-managed primary or
+// Redis Cluster described by cfg. The cache's keys live under the configured
+// prefix followed by namespace, which must differ between servers so that
+// each keeps its own keys and LRU index.
+func newRedisCache(cfg config.Config, namespace string) (*redis_cache.Cache, error) {
+	tlsConfig, err := cfg.Redis.TLS.Load()
+	if err != nil {
+		return nil, err
+	}
+	client := redis_cache.ClientOptions{
+		Addr:          cfg.Redis.Addr,
+		MasterName:    cfg.Redis.MasterName,
+		SentinelAddrs: cfg.Redis.SentinelAddrs,
+		ClusterAddrs:  cfg.Redis.ClusterAddrs,
+		Username:      cfg.Redis.Username,
+		Password:      cfg.Redis.Password,
+		DB:            cfg.Redis.DB,
+		TLSConfig:     tlsConfig,
+		PoolSize:      cfg.Redis.PoolSize,
+		DialTimeout:   time.Duration(cfg.Redis.DialTimeout),
+		ReadTimeout:   time.Duration(cfg.Redis.ReadTimeout),
+		WriteTimeout:  time.Duration(cfg.Redis.WriteTimeout),
+		MaxRetries:    cfg.Redis.MaxRetries,
+	}
+	return redis_cache.NewWithOptions(client, cfg.Size,
+		redis_cache.WithTimeout(time.Duration(cfg.Timeout)),
+		redis_cache.WithDefaultTTL(time.Duration(cfg.TTL)),
+		redis_cache.WithPrefix(cfg.Redis.Prefix+namespace),
+	), nil
 }
@@ -0,0 +1,135 @@
+package api_handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRouter builds the /cache routes over any cache.Cache backend.
+func SetupRouter(backend cache.Cache) *gin.Engine {
+	return SetupNamedRouter("cache", backend)
+}
+
+// SetupNamedRouter is SetupRouter with the backend label used by /metrics.
+func SetupNamedRouter(name string, backend cache.Cache) *gin.Engine {
+	router := gin.Default()
+
+	reporter, _ := backend.(cache.StatsReporter)
+	collector := metrics.New(name, reporter)
+	router.Use(collector.Middleware())
+	router.GET("/metrics", collector.Handler())
+
+	// Handlers call the instrumented cache; backend is kept for its optional
+	// interfaces.
+	store := metrics.Instrument(backend, collector)
+
+	router.POST("/cache", func(c *gin.Context) {
+		data, ttl, ok := bindEntry(c)
+		if !ok {
+			return
+		}
+
+		if err := store.Set(c.Request.Context(), data.Key, data.Value, ttl); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Key set successfully"})
+	})
+
+	router.GET("/cache", func(c *gin.Context) {
+		key := c.Query("key")
+		if key == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
+			return
+		}
+
+		value, err := store.Get(c.Request.Context(), key)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"key": key, "value": value})
+	})
+
+	router.DELETE("/cache", func(c *gin.Context) {
+		key := c.Query("key")
+		if key == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
+			return
+		}
+
+		if err := store.Delete(c.Request.Context(), key); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Key deleted successfully"})
+	})
+
+	router.DELETE("/cache/all", func(c *gin.Context) {
+		if err := store.DeleteAll(c.Request.Context()); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "All keys deleted successfully"})
+	})
+
+	router.GET("/cache/all", func(c *gin.Context) {
+		cachedKeys, err := store.Keys(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"keys": cachedKeys})
+	})
+
+	router.GET("/cache/stats", func(c *gin.Context) {
+		if reporter == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Stats not supported by this cache"})
+			return
+		}
+
+		stats, err := reporter.Stats(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"stats": stats, "hit_ratio": stats.HitRatio()})
+	})
+
+	setupBatchRoutes(router, store)
+	setupAtomicRoutes(router, store)
+
+	return router
+}
+
+// parseTTL parses the TTL of a CacheEntry. An empty TTL is zero, which falls
+// back to the backend's default TTL.
+func parseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// respondError maps a backend error to an HTTP response.
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, cache.ErrCacheMiss):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
+	case errors.Is(err, cache.ErrTimeout):
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	case errors.Is(err, cache.ErrVersionMismatch), errors.Is(err, cache.ErrNotInteger):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, errors.ErrUnsupported):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Operation not supported by this cache"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,50 @@
 package api_handler
 
 type CacheEntry struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"` // Any JSON value
+	TTL   string      `json:"ttl"`   // Optional, e.g. "30s"; defaults to the backend TTL
+}
+
+// BatchKeys is the body of the batch get and delete requests.
+type BatchKeys struct {
+	Keys []string `json:"keys"`
+}
+
+// BatchEntries is the body of the batch set request.
+type BatchEntries struct {
+	Entries []CacheEntry `json:"entries"`
+}
+
+// GetResult reports one key of a batch get.
+type GetResult struct {
+	Key   string      `json:"key"`
+	Found bool        `json:"found"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// SetResult reports one entry of a batch set.
+type SetResult struct {
+	Key    string `json:"key"`
+	Stored bool   `json:"stored"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DeleteResult reports one key of a batch delete.
+type DeleteResult struct {
+	Key     string `json:"key"`
+	Deleted bool   `json:"deleted"`
+}
+
+// CounterRequest is the body of the incr and decr requests. Delta defaults to 1.
+type CounterRequest struct {
 	Key   string `json:"key"`
-	Value int    `json:"value"`
+	Delta *int64 `json:"delta"`
+}
+
+// CASEntry is the body of a compare-and-swap: the entry to store and the
+// version, from GET /cache/versioned, that the key must still have.
+type CASEntry struct {
+	CacheEntry
+	Version uint64 `json:"version"`
 }
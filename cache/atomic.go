@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrVersionMismatch is returned by CompareAndSwap when the key was written
+// after the caller read the version it passed.
+var ErrVersionMismatch = errors.New("cache: version mismatch")
+
+// ErrNotInteger is returned by IncrBy when the stored value is not an
+// integer or the result would not fit its type.
+var ErrNotInteger = errors.New("cache: value is not an integer or out of range")
+
+// Incrementer is implemented by backends that add to integer values
+// atomically.
+type Incrementer interface {
+	// IncrBy adds delta to the integer stored under key and returns the
+	// result. A missing key counts as zero and is created with the backend's
+	// default TTL; an existing key keeps its expiry.
+	IncrBy(ctx context.Context, key string, delta int64) (int64, error)
+}
+
+// ConditionalSetter is implemented by backends that write a key only if it
+// is in the expected state, so clients need not race a Get against a Set.
+// ttl follows the rules of Cache.Set.
+type ConditionalSetter interface {
+	// SetNX stores value only if key is absent and reports whether it did.
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	// Replace stores value only if key is present and reports whether it did.
+	Replace(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	// GetVersioned returns the value stored under key and its version, which
+	// changes on every write of the key and is never reused for it.
+	GetVersioned(ctx context.Context, key string) (interface{}, uint64, error)
+	// CompareAndSwap stores value only if key still has version, returning
+	// the new version. It fails with ErrCacheMiss if key is absent and with
+	// ErrVersionMismatch if it was written since.
+	CompareAndSwap(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Batcher is implemented by backends that read, write and delete many keys
+// in one call, such as one Redis round trip instead of one per key.
+type Batcher interface {
+	// GetMulti returns the values of the keys that are present; missing
+	// keys are left out of the map.
+	GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error)
+	// SetMulti stores every item with the same ttl, which follows the rules
+	// of Cache.Set.
+	SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error
+	// DeleteMulti removes keys and returns those that were present.
+	DeleteMulti(ctx context.Context, keys []string) ([]string, error)
+}
+
+// GetMulti reads keys from c in one call if it is a Batcher, and one key
+// at a time otherwise.
+func GetMulti(ctx context.Context, c Cache, keys []string) (map[string]interface{}, error) {
+	if b, ok := c.(Batcher); ok {
+		return b.GetMulti(ctx, keys)
+	}
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		value, err := c.Get(ctx, key)
+		if errors.Is(err, ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// SetMulti writes items to c in one call if it is a Batcher, and one item
+// at a time otherwise.
+func SetMulti(ctx context.Context, c Cache, items map[string]interface{}, ttl time.Duration) error {
+	if b, ok := c.(Batcher); ok {
+		return b.SetMulti(ctx, items, ttl)
+	}
+	for key, value := range items {
+		if err := c.Set(ctx, key, value, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMulti removes keys from c in one call if it is a Batcher, and one
+// key at a time otherwise.
+func DeleteMulti(ctx context.Context, c Cache, keys []string) ([]string, error) {
+	if b, ok := c.(Batcher); ok {
+		return b.DeleteMulti(ctx, keys)
+	}
+	var deleted []string
+	for _, key := range keys {
+		err := c.Delete(ctx, key)
+		if errors.Is(err, ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, key)
+	}
+	return deleted, nil
+}
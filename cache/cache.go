@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ErrCacheMiss indicates that a requested key was not found in the cache.
+// Every backend returns this same value so callers can check for a miss
+// without knowing which backend they are talking to.
+var ErrCacheMiss = errors.New("cache: key not found")
+
+// Cache is the set of operations shared by the in-memory, Redis and
+// multi-level backends.
+type Cache interface {
+	// Get returns the value stored under key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) (interface{}, error)
+	// Set stores value under key. A ttl of zero uses the backend's default TTL
+	// and a negative ttl stores the key without an expiry.
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Delete removes key, returning ErrCacheMiss if the backend knows it was absent.
+	Delete(ctx context.Context, key string) error
+	// DeleteAll removes every key owned by the cache.
+	DeleteAll(ctx context.Context) error
+	// Keys lists the keys currently held by the cache.
+	Keys(ctx context.Context) ([]string, error)
+	// Exists reports whether key is present in the cache.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Close stops background work and releases connections.
+	Close() error
+}
+
+// TTLer is implemented by backends that can report how long a key has left
+// to live. A negative duration means the key never expires.
+type TTLer interface {
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
+// ErrTimeout indicates that a backend did not answer before the operation's
+// deadline. Errors wrapping it also wrap the underlying cause.
+var ErrTimeout = errors.New("cache: operation timed out")
+
+// WrapTimeout returns err wrapped with ErrTimeout if it was caused by a
+// context deadline or a network timeout, and err unchanged otherwise.
+func WrapTimeout(err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// EvictionReason tells an eviction hook why an entry left a cache.
+type EvictionReason int
+
+const (
+	// ReasonCapacity means the entry was evicted to make room.
+	ReasonCapacity EvictionReason = iota + 1
+	// ReasonExpired means the entry's TTL ran out.
+	ReasonExpired
+	// ReasonDeleted means the entry was deleted by key.
+	ReasonDeleted
+	// ReasonCleared means the entry was removed by DeleteAll.
+	ReasonCleared
+)
+
+// String returns the lower-case name of the reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case ReasonCapacity:
+		return "capacity"
+	case ReasonExpired:
+		return "expired"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonCleared:
+		return "cleared"
+	}
+	return fmt.Sprintf("EvictionReason(%d)", int(r))
+}
+
+// Notifier is implemented by backends that report entries leaving them.
+// Hooks run after the backend has released its locks, so they may call back
+// into the cache. A hook may receive a nil value when the backend no longer
+// has it, such as a Redis key that expired.
+type Notifier interface {
+	OnEvict(fn func(key string, value interface{}, reason EvictionReason))
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Loader is implemented by backends that can fill a miss by calling a
+// loader function. Concurrent misses for the same key share one call to
+// loader. A loader that finds nothing should return an error wrapping
+// ErrCacheMiss; backends configured with a negative TTL remember that for a
+// while instead of calling the loader again. A key stored with Set is served
+// as usual even while it has a negative entry, since the cache is checked
+// first.
+type Loader interface {
+	GetOrLoad(ctx context.Context, key string, loader func() (interface{}, error)) (interface{}, error)
+}
+
+// Group coalesces concurrent loads of the same key and remembers keys whose
+// loader found nothing. The zero value is ready to use and caches no
+// negative results.
+type Group[K comparable, V any] struct {
+	// NegativeTTL is how long a key whose loader returned ErrCacheMiss is
+	// reported as missing without calling the loader. Zero disables it.
+	NegativeTTL time.Duration
+
+	lock    sync.Mutex
+	calls   map[K]*call[V]
+	missing map[K]time.Time // Key to the end of its negative entry
+	sweepAt int             // Size of missing that triggers the next sweep
+}
+
+// call is a load in progress or just finished.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// minSweepSize is the smallest negative cache that is swept for expired keys.
+const minSweepSize = 64
+
+// GetOrLoad returns the value from get. On a miss it calls load once for
+// all concurrent callers with the same key and stores the result with set.
+// If set fails, the loaded value is returned together with its error.
+// Callers waiting on another caller's load give up when ctx is done.
+func (g *Group[K, V]) GetOrLoad(ctx context.Context, key K, get func() (V, error), load func() (V, error), set func(V) error) (V, error) {
+	value, err := get()
+	if !errors.Is(err, ErrCacheMiss) {
+		return value, err
+	}
+
+	var zero V
+	g.lock.Lock()
+	if g.isMissing(key, time.Now()) {
+		g.lock.Unlock()
+		return zero, ErrCacheMiss
+	}
+	if c, ok := g.calls[key]; ok {
+		g.lock.Unlock()
+		select {
+		case <-c.done:
+			return c.value, c.err
+		case <-ctx.Done():
+			return zero, WrapTimeout(ctx.Err())
+		}
+	}
+	c := &call[V]{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	defer func() {
+		// A panicking loader must not leave waiters blocked.
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("cache: loader panicked: %v", r)
+			g.finish(key, c, false)
+			panic(r)
+		}
+	}()
+	c.value, c.err = load()
+	missing := errors.Is(c.err, ErrCacheMiss)
+	if missing {
+		c.value, c.err = zero, ErrCacheMiss
+	} else if c.err == nil {
+		c.err = set(c.value)
+	}
+	g.finish(key, c, missing)
+	return c.value, c.err
+}
+
+// finish publishes the result of c and records a negative entry for key if
+// the loader found nothing.
+func (g *Group[K, V]) finish(key K, c *call[V], missing bool) {
+	g.lock.Lock()
+	delete(g.calls, key)
+	if missing && g.NegativeTTL > 0 {
+		g.remember(key, time.Now())
+	}
+	g.lock.Unlock()
+	close(c.done)
+}
+
+// isMissing reports whether key has a live negative entry.
+func (g *Group[K, V]) isMissing(key K, now time.Time) bool {
+	until, ok := g.missing[key]
+	if !ok {
+		return false
+	}
+	if now.Before(until) {
+		return true
+	}
+	delete(g.missing, key)
+	return false
+}
+
+// remember adds a negative entry for key, sweeping expired entries whenever
+// the map has doubled since the last sweep so it stays bounded by the
+// number of keys missed within one NegativeTTL.
+func (g *Group[K, V]) remember(key K, now time.Time) {
+	if g.missing == nil {
+		g.missing = make(map[K]time.Time)
+	}
+	g.missing[key] = now.Add(g.NegativeTTL)
+	if len(g.missing) < max(g.sweepAt, minSweepSize) {
+		return
+	}
+	for k, until := range g.missing {
+		if !now.Before(until) {
+			delete(g.missing, k)
+		}
+	}
+	g.sweepAt = 2 * len(g.missing)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import "context"
+
+// Stats is a snapshot of a backend's activity since it was created and of
+// what it holds now. Counters only cover calls made through this process.
+type Stats struct {
+	Hits      uint64        `json:"hits"`    // Reads that found a value
+	Misses    uint64        `json:"misses"`  // Reads that found nothing
+	Sets      uint64        `json:"sets"`    // Successful writes
+	Deletes   uint64        `json:"deletes"` // Delete calls, whether or not the key existed
+	Evictions EvictionStats `json:"evictions"`
+
+	Entries  int   `json:"entries"`   // Entries held now
+	Bytes    int64 `json:"bytes"`     // Tracked size of the entries; zero if not tracked
+	MaxBytes int64 `json:"max_bytes"` // Byte budget; zero means none
+
+	// Multi-level caches split their hits by the level that served them and
+	// report each level's own stats.
+	L1Hits uint64 `json:"l1_hits,omitempty"`
+	L2Hits uint64 `json:"l2_hits,omitempty"`
+	L1     *Stats `json:"l1,omitempty"`
+	L2     *Stats `json:"l2,omitempty"`
+}
+
+// EvictionStats counts entries that left a cache, by EvictionReason.
+type EvictionStats struct {
+	Capacity uint64 `json:"capacity"`
+	Expired  uint64 `json:"expired"`
+	Deleted  uint64 `json:"deleted"`
+	Cleared  uint64 `json:"cleared"`
+}
+
+// Record adds n entries that left for reason.
+func (e *EvictionStats) Record(reason EvictionReason, n uint64) {
+	switch reason {
+	case ReasonCapacity:
+		e.Capacity += n
+	case ReasonExpired:
+		e.Expired += n
+	case ReasonDeleted:
+		e.Deleted += n
+	case ReasonCleared:
+		e.Cleared += n
+	}
+}
+
+// HitRatio returns the fraction of reads that hit, or 0 before any reads.
+func (s Stats) HitRatio() float64 {
+	reads := s.Hits + s.Misses
+	if reads == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(reads)
+}
+
+// Add adds the counters and sizes of other to s, as when totalling shards.
+func (s *Stats) Add(other Stats) {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Sets += other.Sets
+	s.Deletes += other.Deletes
+	s.Evictions.Capacity += other.Evictions.Capacity
+	s.Evictions.Expired += other.Evictions.Expired
+	s.Evictions.Deleted += other.Evictions.Deleted
+	s.Evictions.Cleared += other.Evictions.Cleared
+	s.Entries += other.Entries
+	s.Bytes += other.Bytes
+	s.MaxBytes += other.MaxBytes
+}
+
+// StatsReporter is implemented by backends that keep Stats.
+type StatsReporter interface {
+	Stats(ctx context.Context) (Stats, error)
+}
@@ -0,0 +1,487 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings for the cache servers.
+type Config struct {
+	Redis    RedisConfig `yaml:"redis"`
+	Size     int         `yaml:"size"`      // Maximum number of entries per cache
+	MaxBytes int64       `yaml:"max_bytes"` // Byte budget per in-memory cache, 0 for none
+	TTL      Duration    `yaml:"ttl"`       // Default entry TTL
+	Timeout  Duration    `yaml:"timeout"`   // Per-operation Redis timeout
+	Ports    PortsConfig `yaml:"ports"`
+
+	// JanitorInterval is how often in-memory caches remove expired entries.
+	JanitorInterval Duration `yaml:"janitor_interval"`
+
+	// ShutdownTimeout is how long the servers wait for in-flight requests
+	// to finish when asked to stop.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout"`
+
+	// SnapshotPath is the file the in-memory server restores its cache from
+	// at startup and saves it to on shutdown. Empty disables snapshots.
+	SnapshotPath string `yaml:"snapshot_path"`
+	// SnapshotInterval is how often the in-memory cache is also saved while
+	// the server runs, 0 for only on shutdown.
+	SnapshotInterval Duration `yaml:"snapshot_interval"`
+	// LogPath is the append-only log of the in-memory server's cache,
+	// replayed at startup to recover from a crash. Empty disables it.
+	LogPath string `yaml:"log_path"`
+	// LogSync is when that log is fsynced: "always", "everysec" or "no".
+	LogSync string `yaml:"log_sync"`
+}
+
+// RedisConfig holds the Redis connection settings. Addr names a single
+// server unless MasterName and SentinelAddrs select a Sentinel-managed
+// primary or ClusterAddrs a Redis Cluster.
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Username string `yaml:"username"` // Redis 6 ACL user, empty for the default user
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+
+	// Prefix namespaces the keys of the servers' Redis caches. Each server
+	// adds its own suffix, so they can share one database without touching
+	// each other's keys. It must not contain braces, which Redis Cluster
+	// reads as hash tags.
+	Prefix string `yaml:"prefix"`
+
+	MasterName    string   `yaml:"master_name"`
+	SentinelAddrs []string `yaml:"sentinel_addrs"`
+	ClusterAddrs  []string `yaml:"cluster_addrs"` // Seed nodes; DB must be 0
+
+	TLS RedisTLSConfig `yaml:"tls"`
+
+	// Connection pool settings. Zero keeps the client's defaults; a
+	// MaxRetries of -1 disables retries.
+	PoolSize     int      `yaml:"pool_size"`
+	MaxRetries   int      `yaml:"max_retries"`
+	DialTimeout  Duration `yaml:"dial_timeout"`
+	ReadTimeout  Duration `yaml:"read_timeout"`
+	WriteTimeout Duration `yaml:"write_timeout"`
+}
+
+// RedisTLSConfig holds the TLS settings of the Redis connections.
+type RedisTLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`   // PEM CA certificates to trust instead of the system's
+	CertFile           string `yaml:"cert_file"` // PEM client certificate
+	KeyFile            string `yaml:"key_file"`  // PEM key of the client certificate
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// Load reads the certificate files and returns the TLS configuration to
+// connect with, or nil if TLS is disabled.
+func (t RedisTLSConfig) Load() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: redis tls: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("config: redis tls: no certificates in %s", t.CAFile)
+		}
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: redis tls: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// PortsConfig holds the port each router listens on.
+type PortsConfig struct {
+	InMemory   int `yaml:"in_memory"`
+	RedisCache int `yaml:"redis_cache"`
+	MultiCache int `yaml:"multicache"`
+}
+
+// Duration is a time.Duration that can be written either as a Go duration
+// string ("90s", "5m") or as a plain number of seconds.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := parseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// String formats the duration like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Set implements flag.Value.
+func (d *Duration) Set(s string) error {
+	return d.UnmarshalText([]byte(s))
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return parsed, nil
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() Config {
+	return Config{
+		Redis: RedisConfig{
+			Addr:   "localhost:6379",
+			Prefix: "cache:",
+		},
+		Size:            3,
+		TTL:             Duration(5 * time.Minute),
+		JanitorInterval: Duration(time.Second),
+		Timeout:         Duration(2 * time.Second),
+		Ports: PortsConfig{
+			InMemory:   8081,
+			RedisCache: 8082,
+			MultiCache: 8080,
+		},
+		ShutdownTimeout: Duration(10 * time.Second),
+		LogSync:         "everysec",
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, a YAML file, environment variables and command-line flags. The
+// file is named by the -config flag or the CONFIG_FILE environment variable.
+// args are the command-line arguments without the program name.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("cache", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	flagValues := flagOverrides(flags)
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return Config{}, err
+	}
+
+	// Only flags given on the command line override the values loaded so far.
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		if apply, ok := flagValues[f.Name]; ok && err == nil {
+			err = apply(&cfg)
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the settings found in a YAML file.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overlays the settings found in environment variables.
+func (c *Config) loadEnv() error {
+	vars := []struct {
+		name  string
+		apply func(string) error
+	}{
+		{"REDIS_ADDR", func(v string) error { c.Redis.Addr = v; return nil }},
+		{"REDIS_USERNAME", func(v string) error { c.Redis.Username = v; return nil }},
+		{"REDIS_PASSWORD", func(v string) error { c.Redis.Password = v; return nil }},
+		{"REDIS_DB", intSetter(&c.Redis.DB)},
+		{"REDIS_PREFIX", func(v string) error { c.Redis.Prefix = v; return nil }},
+		{"REDIS_MASTER_NAME", func(v string) error { c.Redis.MasterName = v; return nil }},
+		{"REDIS_SENTINEL_ADDRS", func(v string) error { c.Redis.SentinelAddrs = splitList(v); return nil }},
+		{"REDIS_CLUSTER_ADDRS", func(v string) error { c.Redis.ClusterAddrs = splitList(v); return nil }},
+		{"REDIS_TLS", boolSetter(&c.Redis.TLS.Enabled)},
+		{"REDIS_TLS_CA_FILE", func(v string) error { c.Redis.TLS.CAFile = v; return nil }},
+		{"REDIS_TLS_CERT_FILE", func(v string) error { c.Redis.TLS.CertFile = v; return nil }},
+		{"REDIS_TLS_KEY_FILE", func(v string) error { c.Redis.TLS.KeyFile = v; return nil }},
+		{"REDIS_TLS_SERVER_NAME", func(v string) error { c.Redis.TLS.ServerName = v; return nil }},
+		{"REDIS_TLS_INSECURE_SKIP_VERIFY", boolSetter(&c.Redis.TLS.InsecureSkipVerify)},
+		{"REDIS_POOL_SIZE", intSetter(&c.Redis.PoolSize)},
+		{"REDIS_MAX_RETRIES", intSetter(&c.Redis.MaxRetries)},
+		{"REDIS_DIAL_TIMEOUT", c.Redis.DialTimeout.Set},
+		{"REDIS_READ_TIMEOUT", c.Redis.ReadTimeout.Set},
+		{"REDIS_WRITE_TIMEOUT", c.Redis.WriteTimeout.Set},
+		{"SIZE", intSetter(&c.Size)},
+		{"MAX_BYTES", int64Setter(&c.MaxBytes)},
+		{"TTL", c.TTL.Set},
+		{"JANITOR_INTERVAL", c.JanitorInterval.Set},
+		{"TIMEOUT", c.Timeout.Set},
+		{"IN_MEMORY_PORT", intSetter(&c.Ports.InMemory)},
+		{"REDIS_CACHE_PORT", intSetter(&c.Ports.RedisCache)},
+		{"MULTICACHE_PORT", intSetter(&c.Ports.MultiCache)},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout.Set},
+		{"SNAPSHOT_PATH", func(v string) error { c.SnapshotPath = v; return nil }},
+		{"SNAPSHOT_INTERVAL", c.SnapshotInterval.Set},
+		{"LOG_PATH", func(v string) error { c.LogPath = v; return nil }},
+		{"LOG_SYNC", func(v string) error { c.LogSync = v; return nil }},
+	}
+
+	for _, v := range vars {
+		value, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := v.apply(value); err != nil {
+			return fmt.Errorf("config: %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+func intSetter(dst *int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func int64Setter(dst *int64) func(string) error {
+	return func(s string) error {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func boolSetter(dst *bool) func(string) error {
+	return func(s string) error {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		*dst = b
+		return nil
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// flagOverrides registers the override flags and returns, per flag name, a
+// function that copies the parsed flag into a Config.
+func flagOverrides(flags *flag.FlagSet) map[string]func(*Config) error {
+	defaults := Default()
+	redisAddr := flags.String("redis-addr", defaults.Redis.Addr, "Redis server address")
+	redisUsername := flags.String("redis-username", "", "Redis ACL username")
+	redisPassword := flags.String("redis-password", "", "Redis password")
+	redisDB := flags.Int("redis-db", defaults.Redis.DB, "Redis database number")
+	redisPrefix := flags.String("redis-prefix", defaults.Redis.Prefix, "namespace of the servers' Redis keys")
+	redisMasterName := flags.String("redis-master-name", "", "name of the Sentinel-managed Redis primary")
+	redisSentinelAddrs := flags.String("redis-sentinel-addrs", "", "comma-separated Redis Sentinel addresses")
+	redisClusterAddrs := flags.String("redis-cluster-addrs", "", "comma-separated Redis Cluster seed addresses")
+	redisTLS := flags.Bool("redis-tls", false, "connect to Redis over TLS")
+	redisTLSCAFile := flags.String("redis-tls-ca-file", "", "PEM CA certificates to trust for Redis")
+	redisTLSCertFile := flags.String("redis-tls-cert-file", "", "PEM client certificate for Redis")
+	redisTLSKeyFile := flags.String("redis-tls-key-file", "", "PEM key of the Redis client certificate")
+	redisTLSServerName := flags.String("redis-tls-server-name", "", "server name to verify the Redis certificate against")
+	redisTLSInsecure := flags.Bool("redis-tls-insecure-skip-verify", false, "do not verify the Redis certificate")
+	redisPoolSize := flags.Int("redis-pool-size", defaults.Redis.PoolSize, "Redis connections per node, 0 for the default")
+	redisMaxRetries := flags.Int("redis-max-retries", defaults.Redis.MaxRetries, "Redis command retries, 0 for the default, -1 for none")
+	redisDialTimeout := defaults.Redis.DialTimeout
+	flags.Var(&redisDialTimeout, "redis-dial-timeout", "Redis connect timeout, 0 for the default")
+	redisReadTimeout := defaults.Redis.ReadTimeout
+	flags.Var(&redisReadTimeout, "redis-read-timeout", "Redis socket read timeout, 0 for the default")
+	redisWriteTimeout := defaults.Redis.WriteTimeout
+	flags.Var(&redisWriteTimeout, "redis-write-timeout", "Redis socket write timeout, 0 for the default")
+	size := flags.Int("size", defaults.Size, "maximum number of entries per cache")
+	maxBytes := flags.Int64("max-bytes", defaults.MaxBytes, "memory budget of each in-memory cache in bytes, 0 for none")
+	ttl := defaults.TTL
+	flags.Var(&ttl, "ttl", "default entry TTL, e.g. 60s or 60")
+	janitorInterval := defaults.JanitorInterval
+	flags.Var(&janitorInterval, "janitor-interval", "how often in-memory caches remove expired entries")
+	timeout := defaults.Timeout
+	flags.Var(&timeout, "timeout", "per-operation Redis timeout")
+	inMemoryPort := flags.Int("in-memory-port", defaults.Ports.InMemory, "in-memory cache server port")
+	redisCachePort := flags.Int("redis-cache-port", defaults.Ports.RedisCache, "Redis cache server port")
+	multiCachePort := flags.Int("multicache-port", defaults.Ports.MultiCache, "multicache server port")
+	shutdownTimeout := defaults.ShutdownTimeout
+	flags.Var(&shutdownTimeout, "shutdown-timeout", "how long to wait for in-flight requests on shutdown")
+	snapshotPath := flags.String("snapshot-path", defaults.SnapshotPath, "file the in-memory cache is restored from and saved to")
+	snapshotInterval := defaults.SnapshotInterval
+	flags.Var(&snapshotInterval, "snapshot-interval", "how often the in-memory cache is saved, 0 for only on shutdown")
+	logPath := flags.String("log-path", defaults.LogPath, "append-only log of the in-memory cache")
+	logSync := flags.String("log-sync", defaults.LogSync, "when the log is fsynced: always, everysec or no")
+
+	return map[string]func(*Config) error{
+		"redis-addr":        func(c *Config) error { c.Redis.Addr = *redisAddr; return nil },
+		"redis-username":    func(c *Config) error { c.Redis.Username = *redisUsername; return nil },
+		"redis-password":    func(c *Config) error { c.Redis.Password = *redisPassword; return nil },
+		"redis-db":          func(c *Config) error { c.Redis.DB = *redisDB; return nil },
+		"redis-prefix":      func(c *Config) error { c.Redis.Prefix = *redisPrefix; return nil },
+		"redis-master-name": func(c *Config) error { c.Redis.MasterName = *redisMasterName; return nil },
+		"redis-sentinel-addrs": func(c *Config) error {
+			c.Redis.SentinelAddrs = splitList(*redisSentinelAddrs)
+			return nil
+		},
+		"redis-cluster-addrs": func(c *Config) error {
+			c.Redis.ClusterAddrs = splitList(*redisClusterAddrs)
+			return nil
+		},
+		"redis-tls":             func(c *Config) error { c.Redis.TLS.Enabled = *redisTLS; return nil },
+		"redis-tls-ca-file":     func(c *Config) error { c.Redis.TLS.CAFile = *redisTLSCAFile; return nil },
+		"redis-tls-cert-file":   func(c *Config) error { c.Redis.TLS.CertFile = *redisTLSCertFile; return nil },
+		"redis-tls-key-file":    func(c *Config) error { c.Redis.TLS.KeyFile = *redisTLSKeyFile; return nil },
+		"redis-tls-server-name": func(c *Config) error { c.Redis.TLS.ServerName = *redisTLSServerName; return nil },
+		"redis-tls-insecure-skip-verify": func(c *Config) error {
+			c.Redis.TLS.InsecureSkipVerify = *redisTLSInsecure
+			return nil
+		},
+		"redis-pool-size":     func(c *Config) error { c.Redis.PoolSize = *redisPoolSize; return nil },
+		"redis-max-retries":   func(c *Config) error { c.Redis.MaxRetries = *redisMaxRetries; return nil },
+		"redis-dial-timeout":  func(c *Config) error { c.Redis.DialTimeout = redisDialTimeout; return nil },
+		"redis-read-timeout":  func(c *Config) error { c.Redis.ReadTimeout = redisReadTimeout; return nil },
+		"redis-write-timeout": func(c *Config) error { c.Redis.WriteTimeout = redisWriteTimeout; return nil },
+		"size":                func(c *Config) error { c.Size = *size; return nil },
+		"max-bytes":           func(c *Config) error { c.MaxBytes = *maxBytes; return nil },
+		"ttl":                 func(c *Config) error { c.TTL = ttl; return nil },
+		"janitor-interval":    func(c *Config) error { c.JanitorInterval = janitorInterval; return nil },
+		"timeout":             func(c *Config) error { c.Timeout = timeout; return nil },
+		"in-memory-port":      func(c *Config) error { c.Ports.InMemory = *inMemoryPort; return nil },
+		"redis-cache-port":    func(c *Config) error { c.Ports.RedisCache = *redisCachePort; return nil },
+		"multicache-port":     func(c *Config) error { c.Ports.MultiCache = *multiCachePort; return nil },
+		"shutdown-timeout":    func(c *Config) error { c.ShutdownTimeout = shutdownTimeout; return nil },
+		"snapshot-path":       func(c *Config) error { c.SnapshotPath = *snapshotPath; return nil },
+		"snapshot-interval":   func(c *Config) error { c.SnapshotInterval = snapshotInterval; return nil },
+		"log-path":            func(c *Config) error { c.LogPath = *logPath; return nil },
+		"log-sync":            func(c *Config) error { c.LogSync = *logSync; return nil },
+	}
+}
+
+// Validate reports the first setting that cannot be used.
+func (c Config) Validate() error {
+	switch {
+	case c.Redis.Addr == "":
+		return errors.New("config: redis address must not be empty")
+	case c.Redis.DB < 0:
+		return fmt.Errorf("config: redis db must not be negative, got %d", c.Redis.DB)
+	case c.Redis.Prefix == "":
+		return errors.New("config: redis prefix must not be empty")
+	case strings.ContainsAny(c.Redis.Prefix, "{}"):
+		return fmt.Errorf("config: redis prefix must not contain braces, got %q", c.Redis.Prefix)
+	case (c.Redis.MasterName == "") != (len(c.Redis.SentinelAddrs) == 0):
+		return errors.New("config: redis master name and sentinel addresses must be set together")
+	case len(c.Redis.ClusterAddrs) > 0 && len(c.Redis.SentinelAddrs) > 0:
+		return errors.New("config: redis cluster and sentinel addresses must not both be set")
+	case len(c.Redis.ClusterAddrs) > 0 && c.Redis.DB != 0:
+		return fmt.Errorf("config: redis cluster only has db 0, got %d", c.Redis.DB)
+	case !c.Redis.TLS.Enabled && (c.Redis.TLS.CAFile != "" || c.Redis.TLS.CertFile != "" || c.Redis.TLS.KeyFile != ""):
+		return errors.New("config: redis tls files are set but tls is not enabled")
+	case (c.Redis.TLS.CertFile == "") != (c.Redis.TLS.KeyFile == ""):
+		return errors.New("config: redis tls cert file and key file must be set together")
+	case c.Redis.PoolSize < 0:
+		return fmt.Errorf("config: redis pool size must not be negative, got %d", c.Redis.PoolSize)
+	case c.Redis.MaxRetries < -1:
+		return fmt.Errorf("config: redis max retries must be -1 or more, got %d", c.Redis.MaxRetries)
+	case c.Redis.DialTimeout < 0 || c.Redis.ReadTimeout < 0 || c.Redis.WriteTimeout < 0:
+		return errors.New("config: redis dial, read and write timeouts must not be negative")
+	case c.Size <= 0:
+		return fmt.Errorf("config: size must be positive, got %d", c.Size)
+	case c.MaxBytes < 0:
+		return fmt.Errorf("config: max bytes must not be negative, got %d", c.MaxBytes)
+	case c.TTL <= 0:
+		return fmt.Errorf("config: ttl must be positive, got %s", c.TTL)
+	case c.JanitorInterval <= 0:
+		return fmt.Errorf("config: janitor interval must be positive, got %s", c.JanitorInterval)
+	case c.Timeout < 0:
+		return fmt.Errorf("config: timeout must not be negative, got %s", c.Timeout)
+	case c.ShutdownTimeout < 0:
+		return fmt.Errorf("config: shutdown timeout must not be negative, got %s", c.ShutdownTimeout)
+	case c.SnapshotInterval < 0:
+		return fmt.Errorf("config: snapshot interval must not be negative, got %s", c.SnapshotInterval)
+	case c.LogSync != "always" && c.LogSync != "everysec" && c.LogSync != "no":
+		return fmt.Errorf("config: log sync must be always, everysec or no, got %q", c.LogSync)
+	}
+
+	ports := []struct {
+		name string
+		port int
+	}{
+		{"in_memory", c.Ports.InMemory},
+		{"redis_cache", c.Ports.RedisCache},
+		{"multicache", c.Ports.MultiCache},
+	}
+	seen := make(map[int]string, len(ports))
+	for _, p := range ports {
+		if p.port <= 0 || p.port > 65535 {
+			return fmt.Errorf("config: %s port must be between 1 and 65535, got %d", p.name, p.port)
+		}
+		if other, ok := seen[p.port]; ok {
+			return fmt.Errorf("config: %s and %s both use port %d", other, p.name, p.port)
+		}
+		seen[p.port] = p.name
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package in_memory
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// IncrBy adds delta to the integer stored under key and returns the result.
+// A missing key counts as zero and is created with the cache-wide TTL; an
+// existing key keeps its expiry and its value keeps its Go type. Values that
+// are not integers, including floats with a fraction, fail with
+// cache.ErrNotInteger, as do results that overflow their type.
+func (c *TypedCache[K, V]) IncrBy(key K, delta int64) (int64, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	var old any
+	entry, err := c.liveEntry(key)
+	if err == nil {
+		old = entry.Value
+	} else {
+		var zero V
+		old = any(zero) // Missing keys start at zero of V, or int64 if V is an interface
+	}
+
+	n, ok := toInt64(old)
+	if old == nil {
+		n, ok = 0, true
+	}
+	if !ok || (delta > 0 && n > math.MaxInt64-delta) || (delta < 0 && n < math.MinInt64-delta) {
+		return 0, cache.ErrNotInteger
+	}
+	n += delta
+	result, ok := fromInt64(n, old)
+	value, isV := result.(V)
+	if !ok || !isV {
+		return 0, cache.ErrNotInteger
+	}
+
+	if entry == nil {
+		if err := c.set(key, value, DefaultExpiration); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+	size, err := c.check(key, value)
+	if err != nil {
+		return 0, err
+	}
+	c.sets++
+	c.rewrite(entry, value, size, entry.TTL)
+	return n, nil
+}
+
+// Incr adds one to the integer stored under key. See IncrBy.
+func (c *TypedCache[K, V]) Incr(key K) (int64, error) {
+	return c.IncrBy(key, 1)
+}
+
+// Decr subtracts one from the integer stored under key. See IncrBy.
+func (c *TypedCache[K, V]) Decr(key K) (int64, error) {
+	return c.IncrBy(key, -1)
+}
+
+// SetNX stores value only if key is absent, and reports whether it did.
+// ttl is as for SetWithTTL.
+func (c *TypedCache[K, V]) SetNX(key K, value V, ttl time.Duration) (bool, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	if _, err := c.liveEntry(key); err == nil {
+		return false, nil
+	}
+	if err := c.set(key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Replace stores value only if key is present, and reports whether it did.
+// ttl is as for SetWithTTL.
+func (c *TypedCache[K, V]) Replace(key K, value V, ttl time.Duration) (bool, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	if _, err := c.liveEntry(key); err != nil {
+		return false, nil
+	}
+	if err := c.set(key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetVersioned is like Get but also returns the entry's version, which
+// changes on every write and is never reused within the cache.
+func (c *TypedCache[K, V]) GetVersioned(key K) (V, uint64, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	value, ok := c.get(key, time.Now())
+	if !ok {
+		return value, 0, ErrCacheMiss
+	}
+	return value, c.cache[key].version, nil
+}
+
+// CompareAndSwap stores value only if key still has version, and returns the
+// new version. It fails with ErrCacheMiss if key is absent and with
+// cache.ErrVersionMismatch if the key was written since version was read.
+func (c *TypedCache[K, V]) CompareAndSwap(key K, value V, version uint64, ttl time.Duration) (uint64, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	entry, err := c.liveEntry(key)
+	if err != nil {
+		return 0, err
+	}
+	if entry.version != version {
+		return 0, cache.ErrVersionMismatch
+	}
+	if err := c.set(key, value, ttl); err != nil {
+		return 0, err
+	}
+	return entry.version, nil
+}
+
+// IncrByCtx is like IncrBy but fails if ctx is already done.
+func (c *TypedCache[K, V]) IncrByCtx(ctx context.Context, key K, delta int64) (int64, error) {
+	if err := ctxErr(ctx); err != nil {
+		return 0, err
+	}
+	return c.IncrBy(key, delta)
+}
+
+// SetNXCtx is like SetNX but fails if ctx is already done.
+func (c *TypedCache[K, V]) SetNXCtx(ctx context.Context, key K, value V, ttl time.Duration) (bool, error) {
+	if err := ctxErr(ctx); err != nil {
+		return false, err
+	}
+	return c.SetNX(key, value, ttl)
+}
+
+// ReplaceCtx is like Replace but fails if ctx is already done.
+func (c *TypedCache[K, V]) ReplaceCtx(ctx context.Context, key K, value V, ttl time.Duration) (bool, error) {
+	if err := ctxErr(ctx); err != nil {
+		return false, err
+	}
+	return c.Replace(key, value, ttl)
+}
+
+// GetVersionedCtx is like GetVersioned but fails if ctx is already done.
+func (c *TypedCache[K, V]) GetVersionedCtx(ctx context.Context, key K) (V, uint64, error) {
+	if err := ctxErr(ctx); err != nil {
+		var zero V
+		return zero, 0, err
+	}
+	return c.GetVersioned(key)
+}
+
+// CompareAndSwapCtx is like CompareAndSwap but fails if ctx is already done.
+func (c *TypedCache[K, V]) CompareAndSwapCtx(ctx context.Context, key K, value V, version uint64, ttl time.Duration) (uint64, error) {
+	if err := ctxErr(ctx); err != nil {
+		return 0, err
+	}
+	return c.CompareAndSwap(key, value, version, ttl)
+}
+
+// IncrBy adds delta to the integer stored under key. See TypedCache.IncrBy.
+func (c *ShardedCache[K, V]) IncrBy(key K, delta int64) (int64, error) {
+	return c.shard(key).IncrBy(key, delta)
+}
+
+// Incr adds one to the integer stored under key.
+func (c *ShardedCache[K, V]) Incr(key K) (int64, error) {
+	return c.shard(key).Incr(key)
+}
+
+// Decr subtracts one from the integer stored under key.
+func (c *ShardedCache[K, V]) Decr(key K) (int64, error) {
+	return c.shard(key).Decr(key)
+}
+
+// SetNX stores value only if key is absent, and reports whether it did.
+func (c *ShardedCache[K, V]) SetNX(key K, value V, ttl time.Duration) (bool, error) {
+	return c.shard(key).SetNX(key, value, ttl)
+}
+
+// Replace stores value only if key is present, and reports whether it did.
+func (c *ShardedCache[K, V]) Replace(key K, value V, ttl time.Duration) (bool, error) {
+	return c.shard(key).Replace(key, value, ttl)
+}
+
+// GetVersioned returns the value stored under key and its version. Versions
+// are unique within a shard, and a key always maps to the same shard.
+func (c *ShardedCache[K, V]) GetVersioned(key K) (V, uint64, error) {
+	return c.shard(key).GetVersioned(key)
+}
+
+// CompareAndSwap stores value only if key still has version. See TypedCache.CompareAndSwap.
+func (c *ShardedCache[K, V]) CompareAndSwap(key K, value V, version uint64, ttl time.Duration) (uint64, error) {
+	return c.shard(key).CompareAndSwap(key, value, version, ttl)
+}
+
+// IncrByCtx is like IncrBy but fails if ctx is already done.
+func (c *ShardedCache[K, V]) IncrByCtx(ctx context.Context, key K, delta int64) (int64, error) {
+	return c.shard(key).IncrByCtx(ctx, key, delta)
+}
+
+// SetNXCtx is like SetNX but fails if ctx is already done.
+func (c *ShardedCache[K, V]) SetNXCtx(ctx context.Context, key K, value V, ttl time.Duration) (bool, error) {
+	return c.shard(key).SetNXCtx(ctx, key, value, ttl)
+}
+
+// ReplaceCtx is like Replace but fails if ctx is already done.
+func (c *ShardedCache[K, V]) ReplaceCtx(ctx context.Context, key K, value V, ttl time.Duration) (bool, error) {
+	return c.shard(key).ReplaceCtx(ctx, key, value, ttl)
+}
+
+// GetVersionedCtx is like GetVersioned but fails if ctx is already done.
+func (c *ShardedCache[K, V]) GetVersionedCtx(ctx context.Context, key K) (V, uint64, error) {
+	return c.shard(key).GetVersionedCtx(ctx, key)
+}
+
+// CompareAndSwapCtx is like CompareAndSwap but fails if ctx is already done.
+func (c *ShardedCache[K, V]) CompareAndSwapCtx(ctx context.Context, key K, value V, version uint64, ttl time.Duration) (uint64, error) {
+	return c.shard(key).CompareAndSwapCtx(ctx, key, value, version, ttl)
+}
+
+// toInt64 converts an integer value, or a float holding an integer as JSON
+// numbers decode to, to int64.
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), uint64(n) <= math.MaxInt64
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case float32:
+		return floatToInt64(float64(n))
+	case float64:
+		return floatToInt64(n)
+	}
+	return 0, false
+}
+
+// floatToInt64 converts f if it is a whole number that fits an int64.
+func floatToInt64(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// fromInt64 converts n to the type of like, or to int64 if like is nil, and
+// reports whether it fits.
+func fromInt64(n int64, like any) (any, bool) {
+	switch like.(type) {
+	case nil, int64:
+		return n, true
+	case int:
+		return int(n), int64(int(n)) == n
+	case int8:
+		return int8(n), int64(int8(n)) == n
+	case int16:
+		return int16(n), int64(int16(n)) == n
+	case int32:
+		return int32(n), int64(int32(n)) == n
+	case uint:
+		return uint(n), n >= 0 && int64(uint(n)) == n
+	case uint8:
+		return uint8(n), n >= 0 && int64(uint8(n)) == n
+	case uint16:
+		return uint16(n), n >= 0 && int64(uint16(n)) == n
+	case uint32:
+		return uint32(n), n >= 0 && int64(uint32(n)) == n
+	case uint64:
+		return uint64(n), n >= 0
+	case float32:
+		return float32(n), true
+	case float64:
+		return float64(n), true
+	}
+	return nil, false
+}
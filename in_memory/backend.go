@@ -0,0 +1,160 @@
+package in_memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// Backend adapts an InMemoryCache or a sharded cache to the cache.Cache interface.
+type Backend struct {
+	cache store
+}
+
+// store is the part of the cache API the adapter needs.
+type store interface {
+	GetCtx(ctx context.Context, key string) (interface{}, error)
+	SetWithTTLCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	DeleteCtx(ctx context.Context, key string) error
+	DeleteAllCtx(ctx context.Context) error
+	GetAllKeysCtx(ctx context.Context) ([]string, error)
+	ExistsCtx(ctx context.Context, key string) (bool, error)
+	GetOrLoad(ctx context.Context, key string, loader func() (interface{}, error)) (interface{}, error)
+	GetMultiCtx(ctx context.Context, keys []string) (map[string]interface{}, error)
+	SetMultiCtx(ctx context.Context, items map[string]interface{}, ttl time.Duration) error
+	DeleteMultiCtx(ctx context.Context, keys []string) ([]string, error)
+	IncrByCtx(ctx context.Context, key string, delta int64) (int64, error)
+	SetNXCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	ReplaceCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	GetVersionedCtx(ctx context.Context, key string) (interface{}, uint64, error)
+	CompareAndSwapCtx(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error)
+	TTL(key string) (time.Duration, error)
+	OnEvict(fn func(key string, value interface{}, reason cache.EvictionReason))
+	Stats() Stats
+	Close() error
+}
+
+var (
+	_ cache.Cache         = (*Backend)(nil)
+	_ cache.TTLer         = (*Backend)(nil)
+	_ cache.Notifier      = (*Backend)(nil)
+	_ cache.StatsReporter = (*Backend)(nil)
+	_ cache.Loader        = (*Backend)(nil)
+	_ cache.Batcher       = (*Backend)(nil)
+
+	_ cache.Incrementer       = (*Backend)(nil)
+	_ cache.ConditionalSetter = (*Backend)(nil)
+)
+
+// NewBackend wraps c so it can be used wherever a cache.Cache is expected.
+func NewBackend(c *InMemoryCache) *Backend {
+	return &Backend{cache: c}
+}
+
+// NewShardedBackend wraps a sharded cache so it can be used wherever a
+// cache.Cache is expected.
+func NewShardedBackend(c *ShardedCache[string, interface{}]) *Backend {
+	return &Backend{cache: c}
+}
+
+// Get returns the value stored under key.
+func (b *Backend) Get(ctx context.Context, key string) (interface{}, error) {
+	return b.cache.GetCtx(ctx, key)
+}
+
+// Set stores value under key for ttl, or for the cache's default TTL if ttl is zero.
+func (b *Backend) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return b.cache.SetWithTTLCtx(ctx, key, value, ttl)
+}
+
+// Delete removes key from the cache.
+func (b *Backend) Delete(ctx context.Context, key string) error {
+	return b.cache.DeleteCtx(ctx, key)
+}
+
+// DeleteAll removes every entry from the cache.
+func (b *Backend) DeleteAll(ctx context.Context) error {
+	return b.cache.DeleteAllCtx(ctx)
+}
+
+// Keys returns all keys in the cache.
+func (b *Backend) Keys(ctx context.Context) ([]string, error) {
+	return b.cache.GetAllKeysCtx(ctx)
+}
+
+// Exists reports whether key is present in the cache.
+func (b *Backend) Exists(ctx context.Context, key string) (bool, error) {
+	return b.cache.ExistsCtx(ctx, key)
+}
+
+// GetMulti returns the values of the keys that are present.
+func (b *Backend) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	return b.cache.GetMultiCtx(ctx, keys)
+}
+
+// SetMulti stores every item for ttl, or for the cache's default TTL if ttl is zero.
+func (b *Backend) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	return b.cache.SetMultiCtx(ctx, items, ttl)
+}
+
+// DeleteMulti removes keys and returns those that were present.
+func (b *Backend) DeleteMulti(ctx context.Context, keys []string) ([]string, error) {
+	return b.cache.DeleteMultiCtx(ctx, keys)
+}
+
+// IncrBy adds delta to the integer stored under key.
+func (b *Backend) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return b.cache.IncrByCtx(ctx, key, delta)
+}
+
+// SetNX stores value only if key is absent, and reports whether it did.
+func (b *Backend) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return b.cache.SetNXCtx(ctx, key, value, ttl)
+}
+
+// Replace stores value only if key is present, and reports whether it did.
+func (b *Backend) Replace(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return b.cache.ReplaceCtx(ctx, key, value, ttl)
+}
+
+// GetVersioned returns the value stored under key and its version.
+func (b *Backend) GetVersioned(ctx context.Context, key string) (interface{}, uint64, error) {
+	return b.cache.GetVersionedCtx(ctx, key)
+}
+
+// CompareAndSwap stores value only if key still has version.
+func (b *Backend) CompareAndSwap(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error) {
+	return b.cache.CompareAndSwapCtx(ctx, key, value, version, ttl)
+}
+
+// GetOrLoad returns the value stored under key, filling a miss from loader.
+func (b *Backend) GetOrLoad(ctx context.Context, key string, loader func() (interface{}, error)) (interface{}, error) {
+	return b.cache.GetOrLoad(ctx, key, loader)
+}
+
+// TTL returns the time left before key expires, or NoExpiration.
+func (b *Backend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if err := ctxErr(ctx); err != nil {
+		return 0, err
+	}
+	return b.cache.TTL(key)
+}
+
+// OnEvict registers fn to be called whenever an entry leaves the cache.
+func (b *Backend) OnEvict(fn func(key string, value interface{}, reason cache.EvictionReason)) {
+	b.cache.OnEvict(fn)
+}
+
+// Stats returns the cache's counters and current size.
+func (b *Backend) Stats(ctx context.Context) (cache.Stats, error) {
+	if err := ctxErr(ctx); err != nil {
+		return cache.Stats{}, err
+	}
+	return b.cache.Stats(), nil
+}
+
+// Close stops the cache's background cleanup and saves its snapshot, if configured.
+func (b *Backend) Close() error {
+	return b.cache.Close()
+}
@@ -0,0 +1,160 @@
+package in_memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// GetMulti returns the values of the keys that are present, taking the lock
+// once for the whole batch. Missing and expired keys are left out.
+func (c *TypedCache[K, V]) GetMulti(keys []K) map[K]V {
+	c.lock.Lock()
+	defer c.unlock()
+
+	values := make(map[K]V, len(keys))
+	now := time.Now()
+	for _, key := range keys {
+		if value, ok := c.get(key, now); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// SetMulti stores every item with the same ttl, as SetWithTTL does, taking
+// the lock once. Items are validated first, so either all of them are
+// stored or, if one is invalid, none are.
+func (c *TypedCache[K, V]) SetMulti(items map[K]V, ttl time.Duration) error {
+	c.lock.Lock()
+	defer c.unlock()
+
+	sizes := make(map[K]int64, len(items))
+	for key, value := range items {
+		size, err := c.check(key, value)
+		if err != nil {
+			return err
+		}
+		sizes[key] = size
+	}
+	for key, value := range items {
+		c.store(key, value, sizes[key], ttl)
+	}
+	return nil
+}
+
+// DeleteMulti removes keys and returns those that were present.
+func (c *TypedCache[K, V]) DeleteMulti(keys []K) []K {
+	c.lock.Lock()
+	defer c.unlock()
+
+	var deleted []K
+	for _, key := range keys {
+		c.deletes++
+		if entry, exists := c.cache[key]; exists {
+			c.removeEntry(entry, cache.ReasonDeleted)
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted
+}
+
+// GetMultiCtx is like GetMulti but fails if ctx is already done.
+func (c *TypedCache[K, V]) GetMultiCtx(ctx context.Context, keys []K) (map[K]V, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.GetMulti(keys), nil
+}
+
+// SetMultiCtx is like SetMulti but fails if ctx is already done.
+func (c *TypedCache[K, V]) SetMultiCtx(ctx context.Context, items map[K]V, ttl time.Duration) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	return c.SetMulti(items, ttl)
+}
+
+// DeleteMultiCtx is like DeleteMulti but fails if ctx is already done.
+func (c *TypedCache[K, V]) DeleteMultiCtx(ctx context.Context, keys []K) ([]K, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.DeleteMulti(keys), nil
+}
+
+// byShard groups keys by the shard that owns them.
+func (c *ShardedCache[K, V]) byShard(keys []K) map[*TypedCache[K, V]][]K {
+	groups := make(map[*TypedCache[K, V]][]K)
+	for _, key := range keys {
+		shard := c.shard(key)
+		groups[shard] = append(groups[shard], key)
+	}
+	return groups
+}
+
+// GetMulti returns the values of the keys that are present, locking each
+// shard involved once.
+func (c *ShardedCache[K, V]) GetMulti(keys []K) map[K]V {
+	values := make(map[K]V, len(keys))
+	for shard, group := range c.byShard(keys) {
+		for key, value := range shard.GetMulti(group) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// SetMulti stores every item with the same ttl, locking each shard involved
+// once. Each shard stores all of its items or none, but an invalid item
+// only stops the shards not yet written.
+func (c *ShardedCache[K, V]) SetMulti(items map[K]V, ttl time.Duration) error {
+	groups := make(map[*TypedCache[K, V]]map[K]V)
+	for key, value := range items {
+		shard := c.shard(key)
+		if groups[shard] == nil {
+			groups[shard] = make(map[K]V)
+		}
+		groups[shard][key] = value
+	}
+	for shard, group := range groups {
+		if err := shard.SetMulti(group, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMulti removes keys and returns those that were present.
+func (c *ShardedCache[K, V]) DeleteMulti(keys []K) []K {
+	var deleted []K
+	for shard, group := range c.byShard(keys) {
+		deleted = append(deleted, shard.DeleteMulti(group)...)
+	}
+	return deleted
+}
+
+// GetMultiCtx is like GetMulti but fails if ctx is already done.
+func (c *ShardedCache[K, V]) GetMultiCtx(ctx context.Context, keys []K) (map[K]V, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.GetMulti(keys), nil
+}
+
+// SetMultiCtx is like SetMulti but fails if ctx is already done.
+func (c *ShardedCache[K, V]) SetMultiCtx(ctx context.Context, items map[K]V, ttl time.Duration) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	return c.SetMulti(items, ttl)
+}
+
+// DeleteMultiCtx is like DeleteMulti but fails if ctx is already done.
+func (c *ShardedCache[K, V]) DeleteMultiCtx(ctx context.Context, keys []K) ([]K, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.DeleteMulti(keys), nil
+}
@@ -0,0 +1,72 @@
+package in_memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// The in-memory cache never blocks on I/O, so the ctx variants only refuse to
+// start once ctx is done. They let callers thread one context through code
+// that may be backed by either an in-memory or a Redis cache.
+
+// ctxErr returns the reason ctx is done, wrapping deadlines in cache.ErrTimeout.
+func ctxErr(ctx context.Context) error {
+	return cache.WrapTimeout(ctx.Err())
+}
+
+// GetCtx is like Get but fails if ctx is already done.
+func (c *TypedCache[K, V]) GetCtx(ctx context.Context, key K) (V, error) {
+	if err := ctxErr(ctx); err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.Get(key)
+}
+
+// SetCtx is like Set but fails if ctx is already done.
+func (c *TypedCache[K, V]) SetCtx(ctx context.Context, key K, value V) error {
+	return c.SetWithTTLCtx(ctx, key, value, DefaultExpiration)
+}
+
+// SetWithTTLCtx is like SetWithTTL but fails if ctx is already done.
+func (c *TypedCache[K, V]) SetWithTTLCtx(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	return c.SetWithTTL(key, value, ttl)
+}
+
+// DeleteCtx is like Delete but fails if ctx is already done.
+func (c *TypedCache[K, V]) DeleteCtx(ctx context.Context, key K) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	return c.Delete(key)
+}
+
+// DeleteAllCtx is like DeleteAll but fails if ctx is already done.
+func (c *TypedCache[K, V]) DeleteAllCtx(ctx context.Context) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	c.DeleteAll()
+	return nil
+}
+
+// ExistsCtx is like Exists but fails if ctx is already done.
+func (c *TypedCache[K, V]) ExistsCtx(ctx context.Context, key K) (bool, error) {
+	if err := ctxErr(ctx); err != nil {
+		return false, err
+	}
+	return c.Exists(key), nil
+}
+
+// GetAllKeysCtx is like GetAllKeys but fails if ctx is already done.
+func (c *TypedCache[K, V]) GetAllKeysCtx(ctx context.Context) ([]K, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.GetAllKeys(), nil
+}
@@ -0,0 +1,110 @@
+package in_memory
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// DefaultJanitorInterval is how often expired entries are removed when
+// Config.JanitorInterval is not set.
+const DefaultJanitorInterval = time.Second
+
+// expiryBatchSize bounds how many expired entries the janitor removes per
+// lock acquisition, so a burst of expiries never stalls readers for long.
+const expiryBatchSize = 128
+
+// expiryHeap orders the entries that have a deadline by that deadline,
+// soonest first. Each entry records its position in index, or -1 when it is
+// not in the heap.
+type expiryHeap[K comparable, V any] []*TypedEntry[K, V]
+
+func (h expiryHeap[K, V]) Len() int { return len(h) }
+
+func (h expiryHeap[K, V]) Less(i, j int) bool { return h[i].TTL.Before(h[j].TTL) }
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	entry := x.(*TypedEntry[K, V])
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	entry.index = -1
+	return entry
+}
+
+// setDeadline changes the expiry of entry and keeps the expiry heap in step.
+// The caller must hold the lock.
+func (c *TypedCache[K, V]) setDeadline(entry *TypedEntry[K, V], deadline time.Time) {
+	entry.TTL = deadline
+	switch {
+	case entry.index >= 0 && deadline.IsZero():
+		heap.Remove(&c.expiry, entry.index)
+	case entry.index >= 0:
+		heap.Fix(&c.expiry, entry.index)
+	case !deadline.IsZero():
+		heap.Push(&c.expiry, entry)
+	}
+}
+
+// unschedule removes entry from the expiry heap. The caller must hold the lock.
+func (c *TypedCache[K, V]) unschedule(entry *TypedEntry[K, V]) {
+	if entry.index >= 0 {
+		heap.Remove(&c.expiry, entry.index)
+	}
+}
+
+// startCleanup removes expired entries every interval until Close is called.
+func (c *TypedCache[K, V]) startCleanup(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanupExpiredEntries()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// cleanupExpiredEntries removes every entry whose deadline has passed, in
+// batches of expiryBatchSize, releasing the lock between batches.
+func (c *TypedCache[K, V]) cleanupExpiredEntries() {
+	for c.expireBatch(time.Now(), expiryBatchSize) == expiryBatchSize {
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+	}
+}
+
+// expireBatch removes up to limit entries that expired by now, soonest
+// deadline first, and returns how many it removed.
+func (c *TypedCache[K, V]) expireBatch(now time.Time, limit int) int {
+	c.lock.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for removed < limit && len(c.expiry) > 0 && c.expiry[0].expired(now) {
+		c.removeEntry(c.expiry[0], cache.ReasonExpired)
+		removed++
+	}
+	return removed
+}
@@ -0,0 +1,42 @@
+package in_memory
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// hashKey hashes a key of any comparable type. Strings and integers are
+// hashed directly; other types fall back to their fmt representation, which
+// is slower but equal for equal keys.
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return mix(seed, uint64(k))
+	case int64:
+		return mix(seed, uint64(k))
+	case int32:
+		return mix(seed, uint64(k))
+	case uint:
+		return mix(seed, uint64(k))
+	case uint64:
+		return mix(seed, k)
+	case uint32:
+		return mix(seed, uint64(k))
+	default:
+		return maphash.String(seed, fmt.Sprint(key))
+	}
+}
+
+// mix hashes an integer key with the seed.
+func mix(seed maphash.Seed, v uint64) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	h.Write(b[:])
+	return h.Sum64()
+}
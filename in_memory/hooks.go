@@ -0,0 +1,45 @@
+package in_memory
+
+import "github.com/Devisree146/Go_project-library.git/cache"
+
+// evictionEvent is an entry that left the cache, waiting to be reported to
+// the OnEvict hooks once the lock is released.
+type evictionEvent[K comparable, V any] struct {
+	key    K
+	value  V
+	reason cache.EvictionReason
+}
+
+// OnEvict registers fn to be called whenever an entry leaves the cache, with
+// the reason it left. Hooks run after the cache's lock is released, in the
+// goroutine whose call removed the entry (the janitor for expiries it
+// finds), so they may use the cache but should not block for long.
+// Overwriting a key does not count as the old value leaving.
+func (c *TypedCache[K, V]) OnEvict(fn func(key K, value V, reason cache.EvictionReason)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.hooks = append(c.hooks, fn)
+}
+
+// record queues an event for entry if any hooks are registered. The caller
+// must hold the lock.
+func (c *TypedCache[K, V]) record(entry *TypedEntry[K, V], reason cache.EvictionReason) {
+	if len(c.hooks) > 0 {
+		c.events = append(c.events, evictionEvent[K, V]{key: entry.Key, value: entry.Value, reason: reason})
+	}
+}
+
+// unlock releases the lock and then reports the events recorded while it
+// was held. Methods that may remove entries defer it instead of Unlock.
+func (c *TypedCache[K, V]) unlock() {
+	events, hooks := c.events, c.hooks
+	c.events = nil
+	c.lock.Unlock()
+
+	for _, event := range events {
+		for _, hook := range hooks {
+			hook(event.key, event.value, event.reason)
+		}
+	}
+}
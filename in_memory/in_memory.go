@@ -1,106 +1,410 @@
 package in_memory
 
 import (
-	"container/list"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+const (
+	// NoExpiration marks an entry that never expires.
+	NoExpiration time.Duration = -1
+	// DefaultExpiration uses the TTL the cache was created with.
+	DefaultExpiration time.Duration = 0
 )
 
-// Entry represents a cache entry with key, value, and TTL.
-type Entry struct {
-	Key   string
-	Value interface{}
+// TypedEntry represents a cache entry with key, value, and TTL.
+// A zero TTL means the entry never expires.
+type TypedEntry[K comparable, V any] struct {
+	Key   K
+	Value V
 	TTL   time.Time
+
+	size       int64     // Bytes charged against the byte budget
+	index      int       // Position in the expiry heap, or -1
+	version    uint64    // Changes on every write; unique within the cache
+	stale      time.Time // Soft deadline after which reads refresh the entry; zero if none
+	reads      int       // Reads since the value was written
+	refreshing bool      // A background refresh is running
 }
 
-// InMemoryCache represents an in-memory cache with LRU eviction.
-type InMemoryCache struct {
-	maxSize int
-	cache   map[string]*list.Element
-	lruList *list.List
-	ttl     time.Duration
-	lock    sync.Mutex
+// expired reports whether the entry's deadline has passed.
+func (e *TypedEntry[K, V]) expired(now time.Time) bool {
+	return !e.TTL.IsZero() && !e.TTL.After(now)
 }
 
-// NewInMemoryCache initializes a new cache with a given maximum size and TTL.
-func NewInMemoryCache(maxSize int, ttl time.Duration) *InMemoryCache {
-	c := &InMemoryCache{
-		maxSize: maxSize,
-		cache:   make(map[string]*list.Element),
-		lruList: list.New(),
-		ttl:     ttl,
+// Entry is the untyped entry stored by InMemoryCache.
+type Entry = TypedEntry[string, interface{}]
+
+// TypedCache represents an in-memory cache whose keys and values have
+// compile-time types. Which entry is evicted when the cache is full is decided
+// by its EvictionPolicy, LRU unless configured otherwise.
+type TypedCache[K comparable, V any] struct {
+	maxSize  int
+	maxBytes int64
+	bytes    int64
+	sizer    Sizer[K, V]
+	cache    map[K]*TypedEntry[K, V]
+	expiry   expiryHeap[K, V]
+	policy   EvictionPolicy[K]
+	hooks    []func(key K, value V, reason cache.EvictionReason)
+	events   []evictionEvent[K, V] // Recorded under the lock, reported by unlock
+	loads    cache.Group[K, V]     // Coalesces GetOrLoad misses
+
+	loader           func(key K) (V, error)
+	softTTL          time.Duration
+	refreshAhead     time.Duration
+	refreshAheadHits int
+
+	snapshotPath    string
+	onSnapshotError func(err error)
+	log             *opLog[K, V] // Nil without Config.LogPath
+	logPath         string
+	logCompactSize  int64
+	compacting      bool       // Whether a compaction was started by logged
+	restoring       bool       // Whether a snapshot or log is being reloaded
+	compactLock     sync.Mutex // Serialises compactions; taken before lock
+
+	hits, misses, sets, deletes uint64
+	evictions                   cache.EvictionStats
+	versions                    uint64 // Last version given to a write
+	ttl                         time.Duration
+	lock                        sync.Mutex
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// InMemoryCache is the untyped cache keyed by string, kept for callers that
+// predate TypedCache.
+type InMemoryCache = TypedCache[string, interface{}]
+
+// Config holds the settings for NewWithConfig.
+type Config[K comparable, V any] struct {
+	// MaxSize is the maximum number of entries. Zero or less means no limit.
+	MaxSize int
+	// TTL is the default lifetime of entries; negative means no expiry.
+	TTL time.Duration
+	// MaxBytes is the maximum total size of the entries as reported by
+	// Sizer. Zero or less means no limit.
+	MaxBytes int64
+	// Sizer measures entries for MaxBytes. Nil selects DefaultSizer when
+	// MaxBytes is set; otherwise sizes are not tracked.
+	Sizer Sizer[K, V]
+	// Policy builds the eviction policy. Nil selects NewLRU.
+	Policy PolicyFactory[K]
+	// JanitorInterval is how often expired entries are removed in the
+	// background. Zero or less selects DefaultJanitorInterval. Expired
+	// entries are never returned, whatever the interval.
+	JanitorInterval time.Duration
+	// NegativeTTL is how long GetOrLoad remembers a key its loader could not
+	// find. Zero, the default, calls the loader on every miss.
+	NegativeTTL time.Duration
+	// Loader reloads entries in the background for SoftTTL and RefreshAhead,
+	// which have no effect without it.
+	Loader func(key K) (V, error)
+	// SoftTTL is how long after a write an entry turns stale. Reads of a
+	// stale entry still return it but start a refresh through Loader; the
+	// entry's TTL remains the hard limit after which it is gone. Zero or
+	// less disables it.
+	SoftTTL time.Duration
+	// RefreshAhead refreshes an entry through Loader when it is read within
+	// this long of its TTL after at least RefreshAheadHits reads since it
+	// was written. Zero or less disables it.
+	RefreshAhead time.Duration
+	// RefreshAheadHits is the number of reads that make an entry hot enough
+	// to refresh ahead. Zero or less selects DefaultRefreshAheadHits.
+	RefreshAheadHits int
+	// SnapshotPath names a file the cache is loaded from when it is created,
+	// if the file exists, and saved to on Close. See SaveSnapshotFile.
+	SnapshotPath string
+	// SnapshotInterval is how often the cache is also saved to SnapshotPath
+	// while it runs, compacting the log if there is one. Zero or less saves
+	// only on Close.
+	SnapshotInterval time.Duration
+	// LogPath names an append-only file recording every change to the
+	// cache, replayed on top of the snapshot when the cache is created so
+	// that it recovers from a crash. SnapshotPath defaults to LogPath with
+	// ".snapshot" appended. See CompactLog.
+	LogPath string
+	// LogSync selects how often the log is forced to disk.
+	LogSync LogSync
+	// LogCompactSize is the log size in bytes that starts a compaction in
+	// the background. Zero selects DefaultLogCompactSize; negative compacts
+	// only on Close and every SnapshotInterval.
+	LogCompactSize int64
+	// OnSnapshotError is called with the errors of the load and of the
+	// periodic saves and log writes, which happen in the background. Nil
+	// ignores them; Close returns the error of its own save.
+	OnSnapshotError func(err error)
+}
+
+// Stats is the snapshot returned by Stats. Entries includes expired entries
+// not yet removed, and Bytes is zero unless sizes are tracked.
+type Stats = cache.Stats
+
+// NewTyped initializes a new typed LRU cache with a given maximum size and TTL.
+func NewTyped[K comparable, V any](maxSize int, ttl time.Duration) *TypedCache[K, V] {
+	return NewWithConfig(Config[K, V]{MaxSize: maxSize, TTL: ttl})
+}
+
+// NewWithConfig initializes a new typed cache from cfg.
+func NewWithConfig[K comparable, V any](cfg Config[K, V]) *TypedCache[K, V] {
+	policy := cfg.Policy
+	if policy == nil {
+		policy = NewLRU[K]
+	}
+	sizer := cfg.Sizer
+	if sizer == nil && cfg.MaxBytes > 0 {
+		sizer = DefaultSizer[K, V]
+	}
+	c := &TypedCache[K, V]{
+		maxSize:  cfg.MaxSize,
+		maxBytes: max(cfg.MaxBytes, 0),
+		sizer:    sizer,
+		cache:    make(map[K]*TypedEntry[K, V]),
+		policy:   policy(cfg.MaxSize),
+		ttl:      cfg.TTL,
+		done:     make(chan struct{}),
+	}
+	c.loads.NegativeTTL = cfg.NegativeTTL
+	c.loader = cfg.Loader
+	c.softTTL = cfg.SoftTTL
+	c.refreshAhead = cfg.RefreshAhead
+	c.refreshAheadHits = cfg.RefreshAheadHits
+	if c.refreshAheadHits <= 0 {
+		c.refreshAheadHits = DefaultRefreshAheadHits
+	}
+	c.snapshotPath = cfg.SnapshotPath
+	c.onSnapshotError = cfg.OnSnapshotError
+	c.logPath = cfg.LogPath
+	if c.logPath != "" && c.snapshotPath == "" {
+		c.snapshotPath = c.logPath + ".snapshot"
+	}
+	c.logCompactSize = cfg.LogCompactSize
+	if c.logCompactSize == 0 {
+		c.logCompactSize = DefaultLogCompactSize
+	}
+	if c.snapshotPath != "" {
+		c.restoreSnapshot()
+		if cfg.SnapshotInterval > 0 {
+			c.wg.Add(1)
+			go c.startSnapshots(cfg.SnapshotInterval)
+		}
+	}
+	if c.logPath != "" {
+		c.openLog(c.logPath, cfg.LogSync)
+		c.wg.Add(1)
+		go c.startLogFlusher()
+	}
+	interval := cfg.JanitorInterval
+	if interval <= 0 {
+		interval = DefaultJanitorInterval
 	}
 	// Start a background cleanup goroutine
-	go c.startCleanup()
+	c.wg.Add(1)
+	go c.startCleanup(interval)
 	return c
 }
 
-// Set adds or updates a key-value pair in the cache and handles LRU eviction.
-func (c *InMemoryCache) Set(key string, value interface{}) error {
+// NewInMemoryCache initializes a new cache with a given maximum size and TTL.
+func NewInMemoryCache(maxSize int, ttl time.Duration) *InMemoryCache {
+	return NewTyped[string, interface{}](maxSize, ttl)
+}
+
+// Set adds or updates a key-value pair in the cache, evicting an entry chosen
+// by the eviction policy if the cache is full.
+func (c *TypedCache[K, V]) Set(key K, value V) error {
+	return c.SetWithTTL(key, value, DefaultExpiration)
+}
+
+// SetWithTTL is like Set but the entry expires after ttl instead of the
+// cache-wide TTL. Pass NoExpiration to keep the entry until it is evicted or
+// deleted, or DefaultExpiration to use the cache-wide TTL.
+func (c *TypedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
 
+	return c.set(key, value, ttl)
+}
+
+// set stores value under key. The caller must hold the lock.
+func (c *TypedCache[K, V]) set(key K, value V, ttl time.Duration) error {
+	size, err := c.check(key, value)
+	if err != nil {
+		return err
+	}
+	c.store(key, value, size, ttl)
+	return nil
+}
+
+// check validates an item and returns the size it would be charged.
+func (c *TypedCache[K, V]) check(key K, value V) (int64, error) {
 	// Validate key and value
-	if key == "" {
-		return fmt.Errorf("key cannot be empty")
+	var zero K
+	if key == zero {
+		return 0, fmt.Errorf("key cannot be empty")
 	}
-	if value == nil {
-		return fmt.Errorf("value cannot be nil")
+	if any(value) == nil {
+		return 0, fmt.Errorf("value cannot be nil")
 	}
 
-	// If the key already exists, update the value and TTL, and move it to the front.
-	if element, exists := c.cache[key]; exists {
-		c.lruList.MoveToFront(element)
-		element.Value.(*Entry).Value = value
-		element.Value.(*Entry).TTL = time.Now().Add(c.ttl)
-		return nil
+	var size int64
+	if c.sizer != nil {
+		size = c.sizer(key, value)
+	}
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return 0, ErrEntryTooLarge
 	}
+	return size, nil
+}
 
-	// If the cache is at its maximum size, evict the least recently used element.
-	if len(c.cache) >= c.maxSize {
-		c.evict()
+// store adds or updates an item that passed check. The caller must hold the lock.
+func (c *TypedCache[K, V]) store(key K, value V, size int64, ttl time.Duration) {
+	c.sets++
+
+	// If the key already exists, update the value and TTL, and record the access.
+	if entry, exists := c.cache[key]; exists {
+		c.rewrite(entry, value, size, c.deadline(ttl))
+		return
 	}
 
+	// If the cache is full, evict the entries chosen by the policy.
+	c.evict(1, size)
+
 	// Add the new key-value pair to the cache.
-	newEntry := &Entry{
+	entry := &TypedEntry[K, V]{
 		Key:   key,
 		Value: value,
-		TTL:   time.Now().Add(c.ttl),
+		size:  size,
+		index: -1,
 	}
-	element := c.lruList.PushFront(newEntry)
-	c.cache[key] = element
+	c.setDeadline(entry, c.deadline(ttl))
+	c.written(entry)
+	c.cache[key] = entry
+	c.bytes += size
+	c.policy.Add(key)
+}
 
-	return nil
+// rewrite replaces the value and deadline of an existing entry and records
+// the access. The caller must hold the lock.
+func (c *TypedCache[K, V]) rewrite(entry *TypedEntry[K, V], value V, size int64, deadline time.Time) {
+	c.policy.Access(entry.Key)
+	entry.Value = value
+	c.setDeadline(entry, deadline)
+	c.written(entry)
+	c.bytes += size - entry.size
+	entry.size = size
+	// A larger value may push the cache over its byte budget.
+	c.evict(0, 0)
 }
 
-// Get fetches the value from the cache and moves the entry to the front of the LRU list.
-func (c *InMemoryCache) Get(key string) (interface{}, error) {
+// Get fetches the value from the cache and records the access with the eviction policy.
+func (c *TypedCache[K, V]) Get(key K) (V, error) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
+
+	if value, ok := c.get(key, time.Now()); ok {
+		return value, nil
+	}
+	var zero V
+	return zero, ErrCacheMiss
+}
 
+// get looks up key at now, counting the hit or miss. The caller must hold the lock.
+func (c *TypedCache[K, V]) get(key K, now time.Time) (V, bool) {
 	// Check if the key exists in the cache.
-	if element, exists := c.cache[key]; exists {
+	if entry, exists := c.cache[key]; exists {
 		// Check if the entry has expired.
-		if element.Value.(*Entry).TTL.After(time.Now()) {
-			c.lruList.MoveToFront(element)
-			return element.Value.(*Entry).Value, nil
+		if !entry.expired(now) {
+			c.policy.Access(key)
+			c.hits++
+			c.maybeRefresh(entry, now)
+			return entry.Value, true
 		}
 		// If the entry has expired, remove it.
-		c.removeElement(element)
+		c.removeEntry(entry, cache.ReasonExpired)
 	}
 
-	return nil, ErrCacheMiss
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+// TTL returns the time left before key expires, or NoExpiration if it never does.
+func (c *TypedCache[K, V]) TTL(key K) (time.Duration, error) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	entry, err := c.liveEntry(key)
+	if err != nil {
+		return 0, err
+	}
+	if entry.TTL.IsZero() {
+		return NoExpiration, nil
+	}
+	return time.Until(entry.TTL), nil
+}
+
+// Expire resets the expiry of an existing key to ttl from now. It accepts the
+// same special values as SetWithTTL.
+func (c *TypedCache[K, V]) Expire(key K, ttl time.Duration) error {
+	c.lock.Lock()
+	defer c.unlock()
+
+	entry, err := c.liveEntry(key)
+	if err != nil {
+		return err
+	}
+	c.setDeadline(entry, c.deadline(ttl))
+	var zero V
+	c.logged(logExpire, key, zero, entry.TTL)
+	return nil
+}
+
+// Persist removes the expiry from an existing key.
+func (c *TypedCache[K, V]) Persist(key K) error {
+	return c.Expire(key, NoExpiration)
+}
+
+// liveEntry returns the unexpired entry for key, removing it if it has expired.
+// The caller must hold the lock.
+func (c *TypedCache[K, V]) liveEntry(key K) (*TypedEntry[K, V], error) {
+	entry, exists := c.cache[key]
+	if !exists {
+		return nil, ErrCacheMiss
+	}
+	if entry.expired(time.Now()) {
+		c.removeEntry(entry, cache.ReasonExpired)
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
+// deadline converts a TTL into an absolute expiry time. The zero time means
+// the entry never expires.
+func (c *TypedCache[K, V]) deadline(ttl time.Duration) time.Time {
+	if ttl == DefaultExpiration {
+		ttl = c.ttl
+	}
+	if ttl < 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
 }
 
 // Delete removes an entry from the cache.
-func (c *InMemoryCache) Delete(key string) error {
+func (c *TypedCache[K, V]) Delete(key K) error {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
 
-	if element, exists := c.cache[key]; exists {
-		c.removeElement(element)
+	c.deletes++
+	if entry, exists := c.cache[key]; exists {
+		c.removeEntry(entry, cache.ReasonDeleted)
 		return nil
 	}
 
@@ -108,30 +412,96 @@ func (c *InMemoryCache) Delete(key string) error {
 }
 
 // DeleteAll removes all entries from the cache.
-func (c *InMemoryCache) DeleteAll() {
+func (c *TypedCache[K, V]) DeleteAll() {
+	c.lock.Lock()
+	defer c.unlock()
+
+	c.clear()
+	var zero K
+	var none V
+	c.logged(logClear, zero, none, time.Time{})
+}
+
+// clear removes all entries. The caller must hold the lock.
+func (c *TypedCache[K, V]) clear() {
+	for _, entry := range c.cache {
+		c.record(entry, cache.ReasonCleared)
+	}
+	c.evictions.Record(cache.ReasonCleared, uint64(len(c.cache)))
+	c.policy.Reset()
+	c.cache = make(map[K]*TypedEntry[K, V])
+	c.expiry = nil
+	c.bytes = 0
+}
+
+// Stats returns the cache's counters and its current entry count and byte usage.
+func (c *TypedCache[K, V]) Stats() Stats {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.lruList.Init()
-	c.cache = make(map[string]*list.Element)
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Sets:      c.sets,
+		Deletes:   c.deletes,
+		Evictions: c.evictions,
+		Entries:   len(c.cache),
+		Bytes:     c.bytes,
+		MaxBytes:  c.maxBytes,
+	}
 }
 
-// evict removes the least recently used entry from the cache.
-func (c *InMemoryCache) evict() {
-	element := c.lruList.Back()
-	if element != nil {
-		c.removeElement(element)
+// evict removes entries chosen by the eviction policy until the given number
+// of new entries, totalling bytes, fit within the cache's limits. The caller
+// must hold the lock.
+func (c *TypedCache[K, V]) evict(entries int, bytes int64) {
+	full := func() bool {
+		return (c.maxSize > 0 && len(c.cache)+entries > c.maxSize) ||
+			(c.maxBytes > 0 && c.bytes+bytes > c.maxBytes)
+	}
+	// Expiries are not logged, so a replayed log can hold entries that
+	// expired long ago. Drop those before evicting live ones.
+	if c.restoring {
+		now := time.Now()
+		for full() && len(c.expiry) > 0 && c.expiry[0].expired(now) {
+			c.removeEntry(c.expiry[0], cache.ReasonExpired)
+		}
+	}
+	for full() {
+		key, ok := c.policy.Evict()
+		if !ok {
+			return
+		}
+		if entry, exists := c.cache[key]; exists {
+			c.dropEntry(entry, cache.ReasonCapacity)
+		}
 	}
 }
 
-// removeElement removes a specific element from the linked list and hash map.
-func (c *InMemoryCache) removeElement(element *list.Element) {
-	c.lruList.Remove(element)
-	delete(c.cache, element.Value.(*Entry).Key)
+// removeEntry removes an entry from the hash map and the eviction policy.
+func (c *TypedCache[K, V]) removeEntry(entry *TypedEntry[K, V], reason cache.EvictionReason) {
+	c.policy.Remove(entry.Key)
+	c.dropEntry(entry, reason)
+}
+
+// dropEntry removes an entry from the hash map and the expiry heap,
+// releasing its bytes, and records why it left. The eviction policy must
+// already have forgotten it.
+func (c *TypedCache[K, V]) dropEntry(entry *TypedEntry[K, V], reason cache.EvictionReason) {
+	c.record(entry, reason)
+	c.evictions.Record(reason, 1)
+	c.unschedule(entry)
+	c.bytes -= entry.size
+	delete(c.cache, entry.Key)
+	// Expiries replay by themselves; other removals must be logged.
+	if reason != cache.ReasonExpired {
+		var zero V
+		c.logged(logDelete, entry.Key, zero, time.Time{})
+	}
 }
 
 // Exists checks if a key is present in the cache.
-func (c *InMemoryCache) Exists(key string) bool {
+func (c *TypedCache[K, V]) Exists(key K) bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -140,11 +510,11 @@ func (c *InMemoryCache) Exists(key string) bool {
 }
 
 // GetAllKeys returns a slice of all keys in the cache.
-func (c *InMemoryCache) GetAllKeys() []string {
+func (c *TypedCache[K, V]) GetAllKeys() []K {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	keys := make([]string, 0, len(c.cache))
+	keys := make([]K, 0, len(c.cache))
 	for key := range c.cache {
 		keys = append(keys, key)
 	}
@@ -152,28 +522,31 @@ func (c *InMemoryCache) GetAllKeys() []string {
 	return keys
 }
 
-// startCleanup starts a background goroutine to periodically remove expired entries.
-func (c *InMemoryCache) startCleanup() {
-	ticker := time.NewTicker(c.ttl)
-	defer ticker.Stop()
-
-	for {
-		<-ticker.C
-		c.cleanupExpiredEntries()
-	}
-}
-
-// cleanupExpiredEntries removes expired entries from the cache.
-func (c *InMemoryCache) cleanupExpiredEntries() {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	for _, element := range c.cache {
-		if element.Value.(*Entry).TTL.Before(time.Now()) {
-			c.removeElement(element)
+// Close stops the background cleanup goroutine and waits for refreshes in
+// progress. The cache stays usable afterwards, but expired entries are only
+// removed when they are looked up and stale entries are no longer refreshed.
+// With Config.SnapshotPath or LogPath set, Close saves a final snapshot,
+// compacting and closing the log, and returns its error. Calling Close more than once is safe.
+func (c *TypedCache[K, V]) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		// Under the lock so no refresh starts after Wait begins.
+		c.lock.Lock()
+		close(c.done)
+		c.lock.Unlock()
+		c.wg.Wait()
+		if c.snapshotPath != "" {
+			err = c.checkpoint()
 		}
-	}
+		err = errors.Join(err, c.closeLog())
+	})
+	c.wg.Wait()
+	return err
 }
 
 // ErrCacheMiss indicates that a requested key was not found in the cache.
-var ErrCacheMiss = errors.New("cache: key not found")
+var ErrCacheMiss = cache.ErrCacheMiss
+
+// ErrEntryTooLarge is returned when a single entry is bigger than the cache's
+// whole byte budget.
+var ErrEntryTooLarge = errors.New("entry exceeds the cache's byte budget")
@@ -0,0 +1,21 @@
+package in_memory
+
+import "context"
+
+// GetOrLoad returns the value stored under key. On a miss it calls loader,
+// once for all concurrent callers with the same key, and stores the result
+// with the cache-wide TTL. If loader returns an error wrapping ErrCacheMiss,
+// GetOrLoad returns ErrCacheMiss and, when Config.NegativeTTL is set, keeps
+// returning it for that long without calling a loader.
+func (c *TypedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader func() (V, error)) (V, error) {
+	return c.loads.GetOrLoad(ctx, key,
+		func() (V, error) { return c.GetCtx(ctx, key) },
+		loader,
+		func(value V) error { return c.SetWithTTLCtx(ctx, key, value, DefaultExpiration) },
+	)
+}
+
+// GetOrLoad is like TypedCache.GetOrLoad on the shard that owns key.
+func (c *ShardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader func() (V, error)) (V, error) {
+	return c.shard(key).GetOrLoad(ctx, key, loader)
+}
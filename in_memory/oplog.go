@@ -0,0 +1,496 @@
+package in_memory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// LogSync selects how often the operation log is forced to stable storage.
+type LogSync int
+
+const (
+	// SyncEverySecond writes and fsyncs the log once a second, so a crash
+	// loses at most about a second of writes. It is the default.
+	SyncEverySecond LogSync = iota
+	// SyncAlways fsyncs the log before every write returns.
+	SyncAlways
+	// SyncNever writes the log once a second and leaves fsync to the
+	// operating system.
+	SyncNever
+)
+
+// DefaultLogCompactSize is the log size in bytes that triggers a compaction
+// when Config.LogCompactSize is not set.
+const DefaultLogCompactSize = 64 << 20
+
+// logFlushInterval is how often buffered log records are written out, and
+// fsynced under SyncEverySecond.
+const logFlushInterval = time.Second
+
+// maxLogRecord bounds the length read from a record header, so a corrupt
+// header is treated as the end of the log rather than a huge allocation.
+const maxLogRecord = 1 << 30
+
+// logOp is the kind of change a log record describes.
+type logOp uint8
+
+const (
+	logSet    logOp = iota + 1 // Key now holds Value until Expires
+	logDelete                  // Key was deleted or evicted
+	logClear                   // Every key was deleted
+	logExpire                  // Key now expires at Expires, or never if zero
+)
+
+// logRecord is one change to the cache. Each is gob-encoded on its own and
+// framed by its length and CRC-32, so a record torn by a crash is detected
+// and dropped on replay.
+type logRecord[K comparable, V any] struct {
+	Op      logOp
+	Key     K
+	Value   V
+	Expires time.Time
+}
+
+// opLog appends records to the log file. It is used with the cache's lock
+// held.
+type opLog[K comparable, V any] struct {
+	path     string
+	file     *os.File
+	buf      *bufio.Writer
+	sync     LogSync
+	size     int64 // Bytes in the file, including buffered ones
+	frame    bytes.Buffer
+	err      error // First write error; nothing is logged after it
+	reported bool  // Whether err was handed to the flusher
+}
+
+// openOpLog opens the log at path for appending, first cutting it to size
+// to drop a torn record left by a crash.
+func openOpLog[K comparable, V any](path string, size int64, sync LogSync) (*opLog[K, V], error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("in_memory: %w", err)
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("in_memory: %w", err)
+	}
+	return &opLog[K, V]{path: path, file: file, buf: bufio.NewWriter(file), sync: sync, size: size}, nil
+}
+
+// append writes rec to the log, and to disk under SyncAlways. A value gob
+// cannot encode is logged as a delete, so a replay misses the key rather
+// than restoring an older value.
+func (l *opLog[K, V]) append(rec *logRecord[K, V]) {
+	if l.err != nil {
+		return
+	}
+	l.frame.Reset()
+	l.frame.Write(make([]byte, 8)) // Length and checksum, filled in below
+	if err := gob.NewEncoder(&l.frame).Encode(rec); err != nil {
+		if rec.Op == logDelete {
+			l.fail(err) // The key itself cannot be encoded
+			return
+		}
+		l.append(&logRecord[K, V]{Op: logDelete, Key: rec.Key})
+		return
+	}
+	frame := l.frame.Bytes()
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(frame)-8))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(frame[8:]))
+
+	if _, err := l.buf.Write(frame); err != nil {
+		l.fail(err)
+		return
+	}
+	l.size += int64(len(frame))
+	if l.sync == SyncAlways {
+		if err := l.buf.Flush(); err != nil {
+			l.fail(err)
+		} else if err := l.file.Sync(); err != nil {
+			l.fail(err)
+		}
+	}
+}
+
+func (l *opLog[K, V]) fail(err error) {
+	l.err = fmt.Errorf("in_memory: writing log %s: %w", l.path, err)
+}
+
+// flush writes out the buffered records. It returns the file if the sync
+// policy wants it fsynced, and the write error if it was not yet reported.
+func (l *opLog[K, V]) flush() (*os.File, error) {
+	if l.err == nil {
+		if err := l.buf.Flush(); err != nil {
+			l.fail(err)
+		}
+	}
+	if l.err != nil {
+		if l.reported {
+			return nil, nil
+		}
+		l.reported = true
+		return nil, l.err
+	}
+	if l.sync == SyncEverySecond {
+		return l.file, nil
+	}
+	return nil, nil
+}
+
+// rotate moves the records logged so far to path.old and starts an empty
+// log. If path.old is left from a compaction that did not finish, the
+// records are appended to it instead, as its changes may not be in any
+// snapshot yet.
+func (l *opLog[K, V]) rotate() error {
+	if l.err != nil {
+		return l.err
+	}
+	if err := l.buf.Flush(); err != nil {
+		l.fail(err)
+		return l.err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.fail(err)
+		return l.err
+	}
+
+	old := l.path + ".old"
+	_, err := os.Stat(old)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		err = os.Rename(l.path, old)
+	case err == nil:
+		err = appendFile(old, l.path)
+	}
+	if err == nil {
+		err = l.file.Close()
+	}
+	if err != nil {
+		l.fail(err)
+		return l.err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0o600)
+	if err != nil {
+		l.fail(err)
+		return l.err
+	}
+	l.file = file
+	l.buf.Reset(file)
+	l.size = 0
+	return nil
+}
+
+// close flushes, fsyncs and closes the log.
+func (l *opLog[K, V]) close() error {
+	err := l.err
+	if err == nil {
+		err = l.buf.Flush()
+	}
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil && err != l.err {
+		err = fmt.Errorf("in_memory: closing log %s: %w", l.path, err)
+	}
+	return err
+}
+
+// appendFile appends the contents of src to dst and fsyncs it.
+func appendFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// readLog calls apply for each record in the log at path and returns the
+// length of its intact prefix. A torn or corrupt record ends the log
+// silently, and only then may the log be cut to that length. A record that
+// does not decode, such as one written for another value type, or a read
+// error ends it with an error. A missing log is empty.
+func readLog[K comparable, V any](path string, apply func(rec *logRecord[K, V])) (int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("in_memory: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var (
+		valid   int64
+		header  [8]byte
+		payload []byte
+	)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return valid, nil
+			}
+			return valid, fmt.Errorf("in_memory: reading log %s: %w", path, err)
+		}
+		n := binary.BigEndian.Uint32(header[0:4])
+		if n > maxLogRecord {
+			return valid, nil
+		}
+		if cap(payload) < int(n) {
+			payload = make([]byte, n)
+		}
+		payload = payload[:n]
+		if _, err := io.ReadFull(r, payload); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return valid, nil
+			}
+			return valid, fmt.Errorf("in_memory: reading log %s: %w", path, err)
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			return valid, nil
+		}
+
+		var rec logRecord[K, V]
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&rec); err != nil {
+			return valid, fmt.Errorf("in_memory: reading log %s: %w", path, err)
+		}
+		apply(&rec)
+		valid += int64(len(header)) + int64(n)
+	}
+}
+
+// openLog restores the changes recorded at path, and at path.old if a
+// compaction did not finish, on top of the snapshot already loaded, then
+// opens the log for appending. A log that cannot be read to its end is set
+// aside rather than truncated, and the state replayed so far is compacted
+// into the snapshot; if it cannot be set aside, nothing is logged. Called by
+// NewWithConfig.
+func (c *TypedCache[K, V]) openLog(path string, sync LogSync) {
+	var errs []error
+	c.lock.Lock()
+	_, err := os.Stat(path + ".old")
+	compact := err == nil
+	usable := true
+	if compact {
+		if _, err := c.replay(path + ".old"); err != nil {
+			err = setAside(path+".old", err)
+			errs = append(errs, err)
+			usable = !errors.Is(err, errNotSetAside)
+		}
+	}
+	size, err := c.replay(path)
+	if err != nil {
+		err = setAside(path, err)
+		errs = append(errs, err)
+		usable = usable && !errors.Is(err, errNotSetAside)
+		size, compact = 0, true
+	}
+	if usable {
+		c.log, err = openOpLog[K, V](path, size, sync)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.lock.Unlock()
+
+	if compact && c.log != nil {
+		if err := c.CompactLog(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, err := range errs {
+		c.snapshotError(err)
+	}
+}
+
+// errNotSetAside marks a log that could not be read or moved out of the way.
+var errNotSetAside = errors.New("in_memory: log left in place; not logging changes")
+
+// setAside renames the log at path, which readLog failed on with readErr, so
+// that its remaining records are kept for inspection instead of being
+// truncated or appended to. It returns readErr annotated with the outcome.
+func setAside(path string, readErr error) error {
+	aside := fmt.Sprintf("%s.corrupt-%d", path, time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		return errors.Join(readErr, errNotSetAside, fmt.Errorf("in_memory: %w", err))
+	}
+	return fmt.Errorf("%w; log moved to %s", readErr, aside)
+}
+
+// replay applies the records in the log at path like readLog. See restore.
+// The caller must hold the lock.
+func (c *TypedCache[K, V]) replay(path string) (size int64, err error) {
+	c.restore(func() {
+		size, err = readLog(path, c.apply)
+	})
+	return size, err
+}
+
+// apply replays one log record. Entries keep their recorded deadline even if
+// it has passed, since a later record may extend it; expired ones are then
+// removed as usual, or earlier if their slot is needed (see evict). The
+// caller must hold the lock.
+func (c *TypedCache[K, V]) apply(rec *logRecord[K, V]) {
+	switch rec.Op {
+	case logSet:
+		if c.set(rec.Key, rec.Value, NoExpiration) == nil {
+			c.setDeadline(c.cache[rec.Key], rec.Expires)
+		}
+	case logDelete:
+		if entry, exists := c.cache[rec.Key]; exists {
+			c.removeEntry(entry, cache.ReasonDeleted)
+		}
+	case logClear:
+		c.clear()
+	case logExpire:
+		if entry, exists := c.cache[rec.Key]; exists {
+			c.setDeadline(entry, rec.Expires)
+		}
+	}
+}
+
+// logged records a change in the operation log, if there is one, and starts
+// a compaction once the log has grown past its limit. The caller must hold
+// the lock.
+func (c *TypedCache[K, V]) logged(op logOp, key K, value V, expires time.Time) {
+	if c.log == nil {
+		return
+	}
+	c.log.append(&logRecord[K, V]{Op: op, Key: key, Value: value, Expires: expires})
+
+	if c.logCompactSize <= 0 || c.log.size < c.logCompactSize || c.compacting {
+		return
+	}
+	select {
+	case <-c.done:
+		return // Close compacts the log itself
+	default:
+	}
+	c.compacting = true
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		err := c.CompactLog()
+		c.lock.Lock()
+		c.compacting = false
+		c.lock.Unlock()
+		if err != nil {
+			c.snapshotError(err)
+		}
+	}()
+}
+
+// CompactLog replaces the operation log with a snapshot of the cache: the
+// log is moved aside, the snapshot written to Config.SnapshotPath, and the
+// old log removed. A crash at any point leaves a snapshot and logs that
+// replay to the same state. It does nothing if the cache has no log.
+func (c *TypedCache[K, V]) CompactLog() error {
+	c.compactLock.Lock()
+	defer c.compactLock.Unlock()
+
+	c.lock.Lock()
+	if c.log == nil {
+		c.lock.Unlock()
+		return nil
+	}
+	old := c.log.path + ".old"
+	entries := c.snapshot()
+	err := c.log.rotate()
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := writeSnapshotFile(c.snapshotPath, entries); err != nil {
+		return err
+	}
+	if err := os.Remove(old); err != nil {
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	return nil
+}
+
+// startLogFlusher writes out the log every logFlushInterval until Close is
+// called, reporting write errors.
+func (c *TypedCache[K, V]) startLogFlusher() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flushLog()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *TypedCache[K, V]) flushLog() {
+	c.lock.Lock()
+	var (
+		file *os.File
+		err  error
+	)
+	if c.log != nil {
+		file, err = c.log.flush()
+	}
+	c.lock.Unlock()
+
+	// Fsync outside the lock; a compaction may close the file meanwhile,
+	// after syncing it itself.
+	if file != nil {
+		if syncErr := file.Sync(); syncErr != nil && !errors.Is(syncErr, os.ErrClosed) {
+			err = fmt.Errorf("in_memory: syncing log: %w", syncErr)
+		}
+	}
+	if err != nil {
+		c.snapshotError(err)
+	}
+}
+
+// closeLog flushes and closes the log. Later changes are not logged.
+func (c *TypedCache[K, V]) closeLog() error {
+	c.compactLock.Lock()
+	defer c.compactLock.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.log == nil {
+		return nil
+	}
+	err := c.log.close()
+	c.log = nil
+	return err
+}
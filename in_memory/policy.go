@@ -0,0 +1,200 @@
+package in_memory
+
+import "container/list"
+
+// EvictionPolicy decides which key leaves a full cache. The cache tells the
+// policy about every key it stores, reads and removes, and asks it for a
+// victim whenever it holds more entries than it may.
+//
+// Policies are not safe for concurrent use; the cache calls them with its
+// lock held.
+type EvictionPolicy[K comparable] interface {
+	// Add records a key that was just inserted.
+	Add(key K)
+	// Access records a read or update of a key already in the cache.
+	Access(key K)
+	// Remove forgets a key the cache dropped for a reason other than
+	// eviction, such as a delete or expiry.
+	Remove(key K)
+	// Evict chooses a key to evict, forgets it and returns it. It reports
+	// false if the policy tracks no keys.
+	Evict() (K, bool)
+	// Reset forgets every key.
+	Reset()
+}
+
+// orderedPolicy is implemented by policies that can list their keys in
+// eviction order, the next victim first. Snapshots use it so that inserting
+// the keys in that order rebuilds the same policy state.
+type orderedPolicy[K comparable] interface {
+	Order() []K
+}
+
+// PolicyFactory builds an eviction policy for a cache holding up to capacity
+// entries. NewLRU, NewLFU, NewFIFO, NewARC and NewTinyLFU are factories.
+type PolicyFactory[K comparable] func(capacity int) EvictionPolicy[K]
+
+// lruPolicy evicts the least recently used key.
+type lruPolicy[K comparable] struct {
+	order *list.List // Front is most recently used
+	items map[K]*list.Element
+	// touchOnAccess is false for FIFO, which ignores reads.
+	touchOnAccess bool
+}
+
+// NewLRU returns a least-recently-used policy, the cache's default.
+func NewLRU[K comparable](capacity int) EvictionPolicy[K] {
+	return &lruPolicy[K]{order: list.New(), items: make(map[K]*list.Element, capacity), touchOnAccess: true}
+}
+
+// NewFIFO returns a first-in-first-out policy, which evicts the oldest
+// inserted key regardless of how often it is read.
+func NewFIFO[K comparable](capacity int) EvictionPolicy[K] {
+	return &lruPolicy[K]{order: list.New(), items: make(map[K]*list.Element, capacity)}
+}
+
+func (p *lruPolicy[K]) Add(key K) {
+	if element, ok := p.items[key]; ok {
+		p.order.MoveToFront(element)
+		return
+	}
+	p.items[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy[K]) Access(key K) {
+	if element, ok := p.items[key]; ok && p.touchOnAccess {
+		p.order.MoveToFront(element)
+	}
+}
+
+func (p *lruPolicy[K]) Remove(key K) {
+	if element, ok := p.items[key]; ok {
+		p.order.Remove(element)
+		delete(p.items, key)
+	}
+}
+
+func (p *lruPolicy[K]) Evict() (K, bool) {
+	element := p.order.Back()
+	if element == nil {
+		var zero K
+		return zero, false
+	}
+	key := p.order.Remove(element).(K)
+	delete(p.items, key)
+	return key, true
+}
+
+func (p *lruPolicy[K]) Order() []K {
+	keys := make([]K, 0, p.order.Len())
+	for element := p.order.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(K))
+	}
+	return keys
+}
+
+func (p *lruPolicy[K]) Reset() {
+	p.order.Init()
+	p.items = make(map[K]*list.Element)
+}
+
+// lfuPolicy evicts the least frequently used key, breaking ties by recency.
+// Keys are grouped in buckets of equal access count, and the buckets are kept
+// in a list ordered by count, so every operation is O(1).
+type lfuPolicy[K comparable] struct {
+	buckets *list.List // Of *lfuBucket, front has the lowest count
+	items   map[K]*list.Element
+}
+
+type lfuBucket[K comparable] struct {
+	freq int
+	keys *list.List // Of *lfuEntry, front is most recent
+}
+
+type lfuEntry[K comparable] struct {
+	key    K
+	bucket *list.Element
+}
+
+// NewLFU returns a least-frequently-used policy.
+func NewLFU[K comparable](capacity int) EvictionPolicy[K] {
+	return &lfuPolicy[K]{buckets: list.New(), items: make(map[K]*list.Element, capacity)}
+}
+
+// push adds entry to the bucket for freq, creating it after prev (or at the
+// front if prev is nil) when it does not exist.
+func (p *lfuPolicy[K]) push(entry *lfuEntry[K], prev *list.Element, freq int) *list.Element {
+	next := p.buckets.Front()
+	if prev != nil {
+		next = prev.Next()
+	}
+	if next == nil || next.Value.(*lfuBucket[K]).freq != freq {
+		bucket := &lfuBucket[K]{freq: freq, keys: list.New()}
+		if prev == nil {
+			next = p.buckets.PushFront(bucket)
+		} else {
+			next = p.buckets.InsertAfter(bucket, prev)
+		}
+	}
+	entry.bucket = next
+	return next.Value.(*lfuBucket[K]).keys.PushFront(entry)
+}
+
+// unlink removes element from its bucket, dropping the bucket if it empties.
+// It returns the element preceding the bucket if the bucket was dropped, or
+// the bucket itself otherwise.
+func (p *lfuPolicy[K]) unlink(element *list.Element) *list.Element {
+	bucketElement := element.Value.(*lfuEntry[K]).bucket
+	bucket := bucketElement.Value.(*lfuBucket[K])
+	bucket.keys.Remove(element)
+	if bucket.keys.Len() > 0 {
+		return bucketElement
+	}
+	prev := bucketElement.Prev()
+	p.buckets.Remove(bucketElement)
+	return prev
+}
+
+func (p *lfuPolicy[K]) Add(key K) {
+	if _, ok := p.items[key]; ok {
+		p.Access(key)
+		return
+	}
+	p.items[key] = p.push(&lfuEntry[K]{key: key}, nil, 1)
+}
+
+func (p *lfuPolicy[K]) Access(key K) {
+	element, ok := p.items[key]
+	if !ok {
+		return
+	}
+	entry := element.Value.(*lfuEntry[K])
+	freq := entry.bucket.Value.(*lfuBucket[K]).freq
+	prev := p.unlink(element)
+	p.items[key] = p.push(entry, prev, freq+1)
+}
+
+func (p *lfuPolicy[K]) Remove(key K) {
+	if element, ok := p.items[key]; ok {
+		p.unlink(element)
+		delete(p.items, key)
+	}
+}
+
+func (p *lfuPolicy[K]) Evict() (K, bool) {
+	front := p.buckets.Front()
+	if front == nil {
+		var zero K
+		return zero, false
+	}
+	element := front.Value.(*lfuBucket[K]).keys.Back()
+	key := element.Value.(*lfuEntry[K]).key
+	p.unlink(element)
+	delete(p.items, key)
+	return key, true
+}
+
+func (p *lfuPolicy[K]) Reset() {
+	p.buckets.Init()
+	p.items = make(map[K]*list.Element)
+}
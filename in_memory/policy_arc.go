@@ -0,0 +1,129 @@
+package in_memory
+
+import "container/list"
+
+// ARC list identifiers. t1 and t2 hold resident keys seen once and at least
+// twice; b1 and b2 are ghost lists remembering keys recently evicted from them.
+const (
+	arcT1 = iota
+	arcT2
+	arcB1
+	arcB2
+)
+
+type arcEntry[K comparable] struct {
+	key  K
+	list int
+}
+
+// arcPolicy implements Adaptive Replacement Cache. It balances recency (t1)
+// against frequency (t2), moving the target size p of t1 whenever a key is
+// re-added shortly after being evicted from one side. This keeps a single
+// scan from flushing frequently used keys.
+type arcPolicy[K comparable] struct {
+	capacity int
+	p        int
+	lists    [4]*list.List // Front is most recently used
+	items    map[K]*list.Element
+}
+
+// NewARC returns an Adaptive Replacement Cache policy.
+func NewARC[K comparable](capacity int) EvictionPolicy[K] {
+	p := &arcPolicy[K]{capacity: capacity, items: make(map[K]*list.Element, 2*capacity)}
+	for i := range p.lists {
+		p.lists[i] = list.New()
+	}
+	return p
+}
+
+// move unlinks element from its list and pushes its key to the front of dst.
+func (p *arcPolicy[K]) move(element *list.Element, dst int) {
+	entry := element.Value.(*arcEntry[K])
+	p.lists[entry.list].Remove(element)
+	entry.list = dst
+	p.items[entry.key] = p.lists[dst].PushFront(entry)
+}
+
+func (p *arcPolicy[K]) Add(key K) {
+	element, ok := p.items[key]
+	if !ok {
+		p.items[key] = p.lists[arcT1].PushFront(&arcEntry[K]{key: key, list: arcT1})
+		p.trimGhosts()
+		return
+	}
+
+	switch element.Value.(*arcEntry[K]).list {
+	case arcB1:
+		// Evicted from the recency side too early: grow t1.
+		p.p = min(p.capacity, p.p+max(p.lists[arcB2].Len()/p.lists[arcB1].Len(), 1))
+	case arcB2:
+		// Evicted from the frequency side too early: shrink t1.
+		p.p = max(0, p.p-max(p.lists[arcB1].Len()/p.lists[arcB2].Len(), 1))
+	}
+	p.move(element, arcT2)
+	p.trimGhosts()
+}
+
+func (p *arcPolicy[K]) Access(key K) {
+	if element, ok := p.items[key]; ok {
+		if list := element.Value.(*arcEntry[K]).list; list == arcT1 || list == arcT2 {
+			p.move(element, arcT2)
+		}
+	}
+}
+
+func (p *arcPolicy[K]) Remove(key K) {
+	element, ok := p.items[key]
+	if !ok {
+		return
+	}
+	entry := element.Value.(*arcEntry[K])
+	if entry.list == arcT1 || entry.list == arcT2 {
+		p.lists[entry.list].Remove(element)
+		delete(p.items, key)
+	}
+}
+
+func (p *arcPolicy[K]) Evict() (K, bool) {
+	t1, t2 := p.lists[arcT1], p.lists[arcT2]
+	var element *list.Element
+	var ghost int
+	switch {
+	case t1.Len() > 0 && (t1.Len() > p.p || t2.Len() == 0):
+		element, ghost = t1.Back(), arcB1
+	case t2.Len() > 0:
+		element, ghost = t2.Back(), arcB2
+	default:
+		var zero K
+		return zero, false
+	}
+	key := element.Value.(*arcEntry[K]).key
+	p.move(element, ghost)
+	p.trimGhosts()
+	return key, true
+}
+
+// trimGhosts bounds the ghost lists so the policy remembers at most capacity
+// evicted keys.
+func (p *arcPolicy[K]) trimGhosts() {
+	for p.lists[arcB1].Len() > 0 && p.lists[arcT1].Len()+p.lists[arcB1].Len() > p.capacity {
+		p.dropGhost(arcB1)
+	}
+	for p.lists[arcB2].Len() > 0 && p.lists[arcB1].Len()+p.lists[arcB2].Len() > p.capacity {
+		p.dropGhost(arcB2)
+	}
+}
+
+func (p *arcPolicy[K]) dropGhost(ghost int) {
+	element := p.lists[ghost].Back()
+	p.lists[ghost].Remove(element)
+	delete(p.items, element.Value.(*arcEntry[K]).key)
+}
+
+func (p *arcPolicy[K]) Reset() {
+	for _, l := range p.lists {
+		l.Init()
+	}
+	p.items = make(map[K]*list.Element)
+	p.p = 0
+}
@@ -0,0 +1,247 @@
+package in_memory
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// W-TinyLFU segments.
+const (
+	segWindow = iota
+	segProbation
+	segProtected
+)
+
+type tinyLFUEntry[K comparable] struct {
+	key     K
+	segment int
+}
+
+// tinyLFUPolicy implements W-TinyLFU. New keys enter a small LRU window.
+// When the window overflows, its oldest key only displaces the main area's
+// victim if it has been seen more often, as estimated by a count-min sketch.
+// The main area is a segmented LRU: keys hit again while on probation are
+// promoted to the protected segment.
+type tinyLFUPolicy[K comparable] struct {
+	windowCap    int
+	protectedCap int
+	mainCap      int
+	segments     [3]*list.List // Front is most recently used
+	items        map[K]*list.Element
+	sketch       *countMinSketch
+	seed         maphash.Seed
+}
+
+// NewTinyLFU returns a W-TinyLFU policy with a window of 1% of capacity and
+// a main area split 20/80 between probation and protection.
+func NewTinyLFU[K comparable](capacity int) EvictionPolicy[K] {
+	capacity = max(capacity, 1)
+	windowCap := max(capacity/100, 1)
+	mainCap := max(capacity-windowCap, 1)
+	p := &tinyLFUPolicy[K]{
+		windowCap:    windowCap,
+		mainCap:      mainCap,
+		protectedCap: mainCap * 8 / 10,
+		items:        make(map[K]*list.Element, capacity),
+		sketch:       newCountMinSketch(capacity),
+		seed:         maphash.MakeSeed(),
+	}
+	for i := range p.segments {
+		p.segments[i] = list.New()
+	}
+	return p
+}
+
+// move unlinks element from its segment and pushes its key to the front of dst.
+func (p *tinyLFUPolicy[K]) move(element *list.Element, dst int) {
+	entry := element.Value.(*tinyLFUEntry[K])
+	p.segments[entry.segment].Remove(element)
+	entry.segment = dst
+	p.items[entry.key] = p.segments[dst].PushFront(entry)
+}
+
+func (p *tinyLFUPolicy[K]) Add(key K) {
+	p.sketch.increment(hashKey(p.seed, key))
+	if _, ok := p.items[key]; ok {
+		p.touch(key)
+		return
+	}
+	window := p.segments[segWindow]
+	p.items[key] = window.PushFront(&tinyLFUEntry[K]{key: key, segment: segWindow})
+	// While the cache fills up, window overflow goes straight to the main area.
+	if window.Len() > p.windowCap && p.mainLen() < p.mainCap {
+		p.move(window.Back(), segProbation)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) Access(key K) {
+	p.sketch.increment(hashKey(p.seed, key))
+	p.touch(key)
+}
+
+// touch records a hit on a tracked key.
+func (p *tinyLFUPolicy[K]) touch(key K) {
+	element, ok := p.items[key]
+	if !ok {
+		return
+	}
+	switch element.Value.(*tinyLFUEntry[K]).segment {
+	case segWindow:
+		p.segments[segWindow].MoveToFront(element)
+	case segProbation:
+		p.move(element, segProtected)
+		// Demote the oldest protected key to make room.
+		if p.segments[segProtected].Len() > p.protectedCap {
+			p.move(p.segments[segProtected].Back(), segProbation)
+		}
+	case segProtected:
+		p.segments[segProtected].MoveToFront(element)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) Remove(key K) {
+	if element, ok := p.items[key]; ok {
+		p.segments[element.Value.(*tinyLFUEntry[K]).segment].Remove(element)
+		delete(p.items, key)
+	}
+}
+
+// Evict makes room for one key in the window. If the window is full its
+// oldest key moves to the main area, provided it is estimated to be used more
+// often than the main area's victim; otherwise the window key is dropped.
+func (p *tinyLFUPolicy[K]) Evict() (K, bool) {
+	window := p.segments[segWindow]
+	for window.Len() >= p.windowCap {
+		candidate := window.Back()
+		if p.mainLen() < p.mainCap {
+			p.move(candidate, segProbation)
+			continue
+		}
+		victim := p.mainVictim()
+		candidateKey := candidate.Value.(*tinyLFUEntry[K]).key
+		victimKey := victim.Value.(*tinyLFUEntry[K]).key
+		if p.frequency(candidateKey) > p.frequency(victimKey) {
+			p.forget(victim)
+			p.move(candidate, segProbation)
+			return victimKey, true
+		}
+		p.forget(candidate)
+		return candidateKey, true
+	}
+
+	victim := p.mainVictim()
+	if victim == nil {
+		victim = window.Back()
+	}
+	if victim == nil {
+		var zero K
+		return zero, false
+	}
+	p.forget(victim)
+	return victim.Value.(*tinyLFUEntry[K]).key, true
+}
+
+func (p *tinyLFUPolicy[K]) mainLen() int {
+	return p.segments[segProbation].Len() + p.segments[segProtected].Len()
+}
+
+// mainVictim returns the oldest key on probation, falling back to the oldest
+// protected key, or nil if the main area is empty.
+func (p *tinyLFUPolicy[K]) mainVictim() *list.Element {
+	if victim := p.segments[segProbation].Back(); victim != nil {
+		return victim
+	}
+	return p.segments[segProtected].Back()
+}
+
+func (p *tinyLFUPolicy[K]) frequency(key K) uint8 {
+	return p.sketch.estimate(hashKey(p.seed, key))
+}
+
+func (p *tinyLFUPolicy[K]) forget(element *list.Element) {
+	entry := element.Value.(*tinyLFUEntry[K])
+	p.segments[entry.segment].Remove(element)
+	delete(p.items, entry.key)
+}
+
+func (p *tinyLFUPolicy[K]) Reset() {
+	for _, l := range p.segments {
+		l.Init()
+	}
+	p.items = make(map[K]*list.Element)
+	p.sketch.clear()
+}
+
+// sketchDepth is the number of hash rows in the count-min sketch.
+const sketchDepth = 4
+
+// countMinSketch estimates access frequencies in fixed memory. Counters
+// saturate at 15 and are halved every sampleSize increments so old
+// popularity fades.
+type countMinSketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+}
+
+// newCountMinSketch sizes each row to four counters per cached key, rounded
+// up to a power of two, which keeps collisions rare enough that keys seen once
+// do not look popular.
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 16
+	for width < 4*capacity {
+		width <<= 1
+	}
+	s := &countMinSketch{mask: uint64(width - 1), sampleSize: 10 * capacity}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// index derives the counter position for row i from a single 64-bit hash.
+func (s *countMinSketch) index(h uint64, i int) uint64 {
+	h += uint64(i) * 0x9e3779b97f4a7c15
+	h ^= h >> 31
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 29
+	return h & s.mask
+}
+
+func (s *countMinSketch) increment(h uint64) {
+	for i := range s.rows {
+		if idx := s.index(h, i); s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.age()
+	}
+}
+
+func (s *countMinSketch) estimate(h uint64) uint8 {
+	estimate := uint8(15)
+	for i := range s.rows {
+		estimate = min(estimate, s.rows[i][s.index(h, i)])
+	}
+	return estimate
+}
+
+// age halves every counter.
+func (s *countMinSketch) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+func (s *countMinSketch) clear() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.additions = 0
+}
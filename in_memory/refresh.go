@@ -0,0 +1,71 @@
+package in_memory
+
+import "time"
+
+// DefaultRefreshAheadHits is the number of reads that make an entry eligible
+// for refresh-ahead when Config.RefreshAheadHits is not set.
+const DefaultRefreshAheadHits = 3
+
+// written gives an entry whose value was just written a new version and
+// starts its soft TTL. Versions come from one counter per cache so a key
+// that is deleted and written again never repeats one. The caller must hold
+// the lock.
+func (c *TypedCache[K, V]) written(entry *TypedEntry[K, V]) {
+	c.logged(logSet, entry.Key, entry.Value, entry.TTL)
+	c.versions++
+	entry.version = c.versions
+	entry.reads = 0
+	entry.stale = time.Time{}
+	if c.loader == nil || c.softTTL <= 0 {
+		return
+	}
+	// An entry whose hard deadline comes first is never stale.
+	stale := time.Now().Add(c.softTTL)
+	if entry.TTL.IsZero() || stale.Before(entry.TTL) {
+		entry.stale = stale
+	}
+}
+
+// maybeRefresh starts a background refresh of entry, just read at now, if it
+// is stale or hot and close to expiring. At most one refresh per entry runs
+// at a time. The caller must hold the lock.
+func (c *TypedCache[K, V]) maybeRefresh(entry *TypedEntry[K, V], now time.Time) {
+	entry.reads++
+	if c.loader == nil || entry.refreshing {
+		return
+	}
+
+	stale := !entry.stale.IsZero() && !now.Before(entry.stale)
+	ahead := c.refreshAhead > 0 && !entry.TTL.IsZero() &&
+		entry.reads >= c.refreshAheadHits && entry.TTL.Sub(now) <= c.refreshAhead
+	if !stale && !ahead {
+		return
+	}
+
+	select {
+	case <-c.done:
+		return // Closed caches serve stale entries until they expire
+	default:
+	}
+	entry.refreshing = true
+	c.wg.Add(1)
+	go c.refresh(entry, entry.version)
+}
+
+// refresh reloads entry, read at version, through the loader and stores the
+// new value with the cache-wide TTL. The result is dropped if the entry was
+// deleted, expired or overwritten meanwhile. A failed load keeps the old
+// value, which the next read may refresh again.
+func (c *TypedCache[K, V]) refresh(entry *TypedEntry[K, V], version uint64) {
+	defer c.wg.Done()
+
+	value, err := c.loader(entry.Key)
+
+	c.lock.Lock()
+	defer c.unlock()
+	entry.refreshing = false
+	if err != nil || c.cache[entry.Key] != entry || entry.version != version || entry.expired(time.Now()) {
+		return
+	}
+	c.set(entry.Key, value, DefaultExpiration)
+}
@@ -0,0 +1,207 @@
+package in_memory
+
+import (
+	"context"
+	"hash/maphash"
+	"runtime"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// ShardedConfig holds the settings for NewSharded.
+type ShardedConfig[K comparable, V any] struct {
+	// Config applies to the cache as a whole. MaxSize and MaxBytes are split
+	// evenly across the shards. Snapshots and logs are not supported and
+	// the Snapshot and Log settings are ignored.
+	Config[K, V]
+	// Shards is the number of independently locked segments, rounded up to a
+	// power of two. Zero or less selects four per GOMAXPROCS.
+	Shards int
+	// Hasher maps keys to shards. Nil hashes strings and integers directly
+	// and other keys through their fmt representation; set it for struct
+	// keys on hot paths.
+	Hasher func(key K) uint64
+}
+
+// ShardedCache spreads its keys over several TypedCache shards selected by
+// key hash, so operations on different shards never wait for each other's
+// lock. Capacity and eviction are per shard: a full shard evicts its own
+// entries even if others have room, which approximates the configured policy
+// across the whole cache.
+type ShardedCache[K comparable, V any] struct {
+	shards []*TypedCache[K, V]
+	mask   uint64
+	hash   func(key K) uint64
+}
+
+// NewSharded initializes a new sharded cache from cfg.
+func NewSharded[K comparable, V any](cfg ShardedConfig[K, V]) *ShardedCache[K, V] {
+	n := cfg.Shards
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	shards := 1
+	for shards < n {
+		shards <<= 1
+	}
+
+	hash := cfg.Hasher
+	if hash == nil {
+		seed := maphash.MakeSeed()
+		hash = func(key K) uint64 { return hashKey(seed, key) }
+	}
+
+	shardCfg := cfg.Config
+	shardCfg.MaxSize = perShard(cfg.MaxSize, shards)
+	shardCfg.MaxBytes = int64(perShard(int(cfg.MaxBytes), shards))
+	shardCfg.SnapshotPath = ""
+	shardCfg.LogPath = ""
+
+	c := &ShardedCache[K, V]{
+		shards: make([]*TypedCache[K, V], shards),
+		mask:   uint64(shards - 1),
+		hash:   hash,
+	}
+	for i := range c.shards {
+		c.shards[i] = NewWithConfig(shardCfg)
+	}
+	return c
+}
+
+// perShard divides a limit between shards, rounding up so the cache holds at
+// least limit in total. Limits of zero or less stay unlimited.
+func perShard(limit, shards int) int {
+	if limit <= 0 {
+		return 0
+	}
+	return (limit + shards - 1) / shards
+}
+
+// shard returns the shard that owns key.
+func (c *ShardedCache[K, V]) shard(key K) *TypedCache[K, V] {
+	return c.shards[c.hash(key)&c.mask]
+}
+
+// Set adds or updates a key-value pair in the key's shard.
+func (c *ShardedCache[K, V]) Set(key K, value V) error {
+	return c.shard(key).Set(key, value)
+}
+
+// SetWithTTL is like Set but the entry expires after ttl. See TypedCache.SetWithTTL.
+func (c *ShardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	return c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+// Get fetches the value stored under key.
+func (c *ShardedCache[K, V]) Get(key K) (V, error) {
+	return c.shard(key).Get(key)
+}
+
+// TTL returns the time left before key expires, or NoExpiration if it never does.
+func (c *ShardedCache[K, V]) TTL(key K) (time.Duration, error) {
+	return c.shard(key).TTL(key)
+}
+
+// Expire resets the expiry of an existing key to ttl from now.
+func (c *ShardedCache[K, V]) Expire(key K, ttl time.Duration) error {
+	return c.shard(key).Expire(key, ttl)
+}
+
+// Persist removes the expiry from an existing key.
+func (c *ShardedCache[K, V]) Persist(key K) error {
+	return c.shard(key).Persist(key)
+}
+
+// Delete removes an entry from the cache.
+func (c *ShardedCache[K, V]) Delete(key K) error {
+	return c.shard(key).Delete(key)
+}
+
+// DeleteAll removes all entries from the cache, one shard at a time.
+func (c *ShardedCache[K, V]) DeleteAll() {
+	for _, shard := range c.shards {
+		shard.DeleteAll()
+	}
+}
+
+// Exists checks if a key is present in the cache.
+func (c *ShardedCache[K, V]) Exists(key K) bool {
+	return c.shard(key).Exists(key)
+}
+
+// GetAllKeys returns a slice of all keys in the cache. Shards are read one
+// at a time, so the result is not a snapshot of a single instant.
+func (c *ShardedCache[K, V]) GetAllKeys() []K {
+	var keys []K
+	for _, shard := range c.shards {
+		keys = append(keys, shard.GetAllKeys()...)
+	}
+	return keys
+}
+
+// Stats returns the totals over all shards.
+func (c *ShardedCache[K, V]) Stats() Stats {
+	var total Stats
+	for _, shard := range c.shards {
+		total.Add(shard.Stats())
+	}
+	return total
+}
+
+// OnEvict registers fn with every shard. See TypedCache.OnEvict.
+func (c *ShardedCache[K, V]) OnEvict(fn func(key K, value V, reason cache.EvictionReason)) {
+	for _, shard := range c.shards {
+		shard.OnEvict(fn)
+	}
+}
+
+// Close stops the background cleanup of every shard.
+func (c *ShardedCache[K, V]) Close() error {
+	for _, shard := range c.shards {
+		shard.Close()
+	}
+	return nil
+}
+
+// GetCtx is like Get but fails if ctx is already done.
+func (c *ShardedCache[K, V]) GetCtx(ctx context.Context, key K) (V, error) {
+	return c.shard(key).GetCtx(ctx, key)
+}
+
+// SetCtx is like Set but fails if ctx is already done.
+func (c *ShardedCache[K, V]) SetCtx(ctx context.Context, key K, value V) error {
+	return c.shard(key).SetCtx(ctx, key, value)
+}
+
+// SetWithTTLCtx is like SetWithTTL but fails if ctx is already done.
+func (c *ShardedCache[K, V]) SetWithTTLCtx(ctx context.Context, key K, value V, ttl time.Duration) error {
+	return c.shard(key).SetWithTTLCtx(ctx, key, value, ttl)
+}
+
+// DeleteCtx is like Delete but fails if ctx is already done.
+func (c *ShardedCache[K, V]) DeleteCtx(ctx context.Context, key K) error {
+	return c.shard(key).DeleteCtx(ctx, key)
+}
+
+// DeleteAllCtx is like DeleteAll but fails if ctx is already done.
+func (c *ShardedCache[K, V]) DeleteAllCtx(ctx context.Context) error {
+	if err := ctxErr(ctx); err != nil {
+		return err
+	}
+	c.DeleteAll()
+	return nil
+}
+
+// ExistsCtx is like Exists but fails if ctx is already done.
+func (c *ShardedCache[K, V]) ExistsCtx(ctx context.Context, key K) (bool, error) {
+	return c.shard(key).ExistsCtx(ctx, key)
+}
+
+// GetAllKeysCtx is like GetAllKeys but fails if ctx is already done.
+func (c *ShardedCache[K, V]) GetAllKeysCtx(ctx context.Context) ([]K, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	return c.GetAllKeys(), nil
+}
@@ -0,0 +1,114 @@
+package in_memory
+
+import "reflect"
+
+// Sizer reports the number of bytes an entry costs against a cache's byte
+// budget.
+type Sizer[K comparable, V any] func(key K, value V) int64
+
+// entryOverhead approximates the bookkeeping cost of one entry: the entry
+// itself, its map slot and the eviction policy's list element.
+const entryOverhead = 128
+
+// DefaultSizer estimates the size of an entry as the estimated sizes of its
+// key and value plus a fixed per-entry overhead.
+func DefaultSizer[K comparable, V any](key K, value V) int64 {
+	return EstimateSize(key) + EstimateSize(value) + entryOverhead
+}
+
+// EstimateSize approximates the memory held by v, following strings, slices,
+// maps, pointers and interfaces into the data they reference. Shared pointers
+// are counted once. The result is an estimate meant for budgeting, not an
+// exact measure of heap usage.
+func EstimateSize(v interface{}) int64 {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return 0
+	}
+	return sizeOf(value, make(map[uintptr]bool))
+}
+
+// sizeOf returns the inline size of v plus the data it references.
+func sizeOf(v reflect.Value, seen map[uintptr]bool) int64 {
+	return int64(v.Type().Size()) + referenced(v, seen)
+}
+
+// referenced returns the size of the data v references outside its own
+// inline storage.
+func referenced(v reflect.Value, seen map[uintptr]bool) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Type().Elem()
+		n := int64(v.Cap()) * int64(elem.Size())
+		if !flat(elem) {
+			for i := 0; i < v.Len(); i++ {
+				n += referenced(v.Index(i), seen)
+			}
+		}
+		return n
+	case reflect.Array:
+		var n int64
+		if !flat(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				n += referenced(v.Index(i), seen)
+			}
+		}
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return 0
+		}
+		var n int64
+		iter := v.MapRange()
+		for iter.Next() {
+			n += sizeOf(iter.Key(), seen) + sizeOf(iter.Value(), seen)
+		}
+		return n
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		return sizeOf(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem(), seen)
+	case reflect.Struct:
+		var n int64
+		for i := 0; i < v.NumField(); i++ {
+			n += referenced(v.Field(i), seen)
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+// flat reports whether values of type t hold no references, so their size
+// is just t.Size().
+func flat(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return flat(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !flat(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,242 @@
+package in_memory
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// snapshotVersion is the format written by SaveSnapshot. LoadSnapshot
+// rejects any other.
+const snapshotVersion = 1
+
+// snapshotHeader starts a snapshot stream and counts the entries after it.
+type snapshotHeader struct {
+	Version int
+	Entries int
+}
+
+// snapshotEntry is one saved entry. Expires is the absolute deadline, or the
+// zero time if the entry never expires.
+type snapshotEntry[K comparable, V any] struct {
+	Key     K
+	Value   V
+	Expires time.Time
+}
+
+func init() {
+	// The servers store decoded JSON, whose objects and arrays gob must
+	// know to encode them inside an interface{} value.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// SaveSnapshot writes the live entries of the cache to w with encoding/gob,
+// in eviction order so that LoadSnapshot restores the LRU (or FIFO) order.
+// Other policies are restored in write order. Values held in an interface
+// type must be of types registered with gob.Register; JSON objects and
+// arrays are registered by this package.
+func (c *TypedCache[K, V]) SaveSnapshot(w io.Writer) error {
+	c.lock.Lock()
+	entries := c.snapshot()
+	c.lock.Unlock()
+	return writeSnapshot(w, entries)
+}
+
+// writeSnapshot encodes entries in the snapshot format.
+func writeSnapshot[K comparable, V any](w io.Writer, entries []snapshotEntry[K, V]) error {
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion, Entries: len(entries)}); err != nil {
+		return fmt.Errorf("in_memory: writing snapshot: %w", err)
+	}
+	for i := range entries {
+		if err := encoder.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("in_memory: writing snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// snapshot copies the live entries, the next victim first. The caller must
+// hold the lock.
+func (c *TypedCache[K, V]) snapshot() []snapshotEntry[K, V] {
+	now := time.Now()
+	live := make([]*TypedEntry[K, V], 0, len(c.cache))
+	if ordered, ok := c.policy.(orderedPolicy[K]); ok {
+		for _, key := range ordered.Order() {
+			if entry, exists := c.cache[key]; exists {
+				live = append(live, entry)
+			}
+		}
+	} else {
+		for _, entry := range c.cache {
+			live = append(live, entry)
+		}
+		sort.Slice(live, func(i, j int) bool { return live[i].version < live[j].version })
+	}
+
+	entries := make([]snapshotEntry[K, V], 0, len(live))
+	for _, entry := range live {
+		if !entry.expired(now) {
+			entries = append(entries, snapshotEntry[K, V]{Key: entry.Key, Value: entry.Value, Expires: entry.TTL})
+		}
+	}
+	return entries
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot and stores its
+// entries, replacing keys the cache already holds. Each entry keeps the
+// deadline it had when saved, so entries that expired in the meantime are
+// skipped, as are entries the cache rejects, such as those larger than its
+// byte budget. If the snapshot holds more entries than fit, the ones saved
+// as most recently used are kept. Nothing is stored if the snapshot cannot
+// be read.
+func (c *TypedCache[K, V]) LoadSnapshot(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return fmt.Errorf("in_memory: reading snapshot: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("in_memory: unsupported snapshot version %d", header.Version)
+	}
+	entries := make([]snapshotEntry[K, V], 0, min(header.Entries, 1<<16))
+	for i := 0; i < header.Entries; i++ {
+		var entry snapshotEntry[K, V]
+		if err := decoder.Decode(&entry); err != nil {
+			return fmt.Errorf("in_memory: reading snapshot: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	c.lock.Lock()
+	defer c.unlock()
+
+	now := time.Now()
+	c.restore(func() {
+		for _, entry := range entries {
+			ttl := NoExpiration
+			if !entry.Expires.IsZero() {
+				if ttl = entry.Expires.Sub(now); ttl <= 0 {
+					continue
+				}
+			}
+			c.set(entry.Key, entry.Value, ttl)
+		}
+	})
+	return nil
+}
+
+// restore runs fn, which reloads saved entries, without counting its writes
+// and evictions in the cache's stats or reporting them to its hooks: they
+// describe history, not new activity. The caller must hold the lock.
+func (c *TypedCache[K, V]) restore(fn func()) {
+	sets, deletes, evictions, events := c.sets, c.deletes, c.evictions, len(c.events)
+	c.restoring = true
+	defer func() {
+		c.restoring = false
+		c.sets, c.deletes, c.evictions = sets, deletes, evictions
+		c.events = c.events[:events]
+	}()
+	fn()
+}
+
+// SaveSnapshotFile writes a snapshot to path. The file is replaced
+// atomically, so a crash while saving leaves the previous snapshot intact.
+func (c *TypedCache[K, V]) SaveSnapshotFile(path string) error {
+	c.lock.Lock()
+	entries := c.snapshot()
+	c.lock.Unlock()
+	return writeSnapshotFile(path, entries)
+}
+
+// writeSnapshotFile atomically replaces path with a snapshot of entries.
+func writeSnapshotFile[K comparable, V any](path string, entries []snapshotEntry[K, V]) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	defer os.Remove(f.Name()) // Fails harmlessly once renamed
+
+	if err := writeSnapshot(f, entries); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshotFile loads the snapshot saved at path. The error wraps
+// fs.ErrNotExist if there is none.
+func (c *TypedCache[K, V]) LoadSnapshotFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("in_memory: %w", err)
+	}
+	defer f.Close()
+	if err := c.LoadSnapshot(f); err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	return nil
+}
+
+// restoreSnapshot loads Config.SnapshotPath when the cache is created. A
+// missing file is not an error: it is the first start.
+func (c *TypedCache[K, V]) restoreSnapshot() {
+	err := c.LoadSnapshotFile(c.snapshotPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.snapshotError(err)
+	}
+}
+
+// checkpoint saves the cache to Config.SnapshotPath, compacting the log if
+// there is one.
+func (c *TypedCache[K, V]) checkpoint() error {
+	if c.logPath != "" {
+		return c.CompactLog()
+	}
+	return c.SaveSnapshotFile(c.snapshotPath)
+}
+
+// startSnapshots saves the cache to Config.SnapshotPath every interval until
+// Close is called.
+func (c *TypedCache[K, V]) startSnapshots(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.checkpoint(); err != nil {
+				c.snapshotError(err)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// snapshotError reports an error from loading or saving Config.SnapshotPath
+// in the background.
+func (c *TypedCache[K, V]) snapshotError(err error) {
+	if c.onSnapshotError != nil {
+		c.onSnapshotError(err)
+	}
+}
@@ -1,16 +1,86 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/Devisree146/Go_project-library.git/api_handler"
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/config"
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	inMemoryRouter := api_handler.SetupInMemoryRouter()
-	redisCacheRouter := api_handler.SetupRedisCacheRouter()
-	multiCacheRouter := api_handler.SetupMultiCacheRouter()
-
-	// Assuming you want to start each router on different ports
-	go inMemoryRouter.Run(":8081")
-	go redisCacheRouter.Run(":8082")
-	multiCacheRouter.Run(":8080")
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The Redis caches come first: they fail on bad TLS settings, and the
+	// in-memory cache must be closed once created to save its snapshot.
+	redisCacheRouter, redisCache, err := api_handler.SetupRedisCacheRouter(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	multiCacheRouter, multiCache, err := api_handler.SetupMultiCacheRouter(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	inMemoryRouter, inMemoryCache := api_handler.SetupInMemoryRouter(cfg)
+
+	// Each router listens on its own port
+	servers := []*http.Server{
+		newServer(cfg.Ports.InMemory, inMemoryRouter),
+		newServer(cfg.Ports.RedisCache, redisCacheRouter),
+		newServer(cfg.Ports.MultiCache, multiCacheRouter),
+	}
+	caches := []cache.Cache{inMemoryCache, redisCache, multiCache}
+
+	serveErrs := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- fmt.Errorf("%s: %w", srv.Addr, err)
+			}
+		}(srv)
+	}
+
+	// Run until we are asked to stop or a server fails.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case <-ctx.Done():
+		log.Println("shutting down")
+	case err := <-serveErrs:
+		log.Println(err)
+	}
+
+	// Stop accepting requests and let in-flight ones finish, then release
+	// the caches they were using.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("%s: shutdown: %v", srv.Addr, err)
+		}
+	}
+	for _, c := range caches {
+		if err := c.Close(); err != nil {
+			log.Printf("closing cache: %v", err)
+		}
+	}
+}
+
+func newServer(port int, router *gin.Engine) *http.Server {
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: router,
+	}
 }
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// Instrumented is a cache wrapped by Instrument. Besides cache.Cache it
+// offers the batch, counter and conditional operations whatever the wrapped
+// backend supports: batches fall back to one call per key, and the others
+// fail with errors.ErrUnsupported. Other optional interfaces such as
+// cache.TTLer are not forwarded; callers that need them should keep a
+// reference to the backend.
+type Instrumented interface {
+	cache.Cache
+	cache.Batcher
+	cache.Incrementer
+	cache.ConditionalSetter
+}
+
+// Instrument returns backend with the latency and errors of every call
+// recorded in c.
+func Instrument(backend cache.Cache, c *Collector) Instrumented {
+	return &instrumented{backend: backend, metrics: c}
+}
+
+type instrumented struct {
+	backend cache.Cache
+	metrics *Collector
+}
+
+func (i *instrumented) observe(op string, start time.Time, err error) {
+	i.metrics.ObserveOperation(op, time.Since(start), err)
+}
+
+func (i *instrumented) Get(ctx context.Context, key string) (interface{}, error) {
+	start := time.Now()
+	value, err := i.backend.Get(ctx, key)
+	i.observe("get", start, err)
+	return value, err
+}
+
+func (i *instrumented) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	start := time.Now()
+	err := i.backend.Set(ctx, key, value, ttl)
+	i.observe("set", start, err)
+	return err
+}
+
+func (i *instrumented) Delete(ctx context.Context, key string) error {
+	start := time.Now()
+	err := i.backend.Delete(ctx, key)
+	i.observe("delete", start, err)
+	return err
+}
+
+func (i *instrumented) DeleteAll(ctx context.Context) error {
+	start := time.Now()
+	err := i.backend.DeleteAll(ctx)
+	i.observe("delete_all", start, err)
+	return err
+}
+
+func (i *instrumented) Keys(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	keys, err := i.backend.Keys(ctx)
+	i.observe("keys", start, err)
+	return keys, err
+}
+
+func (i *instrumented) Exists(ctx context.Context, key string) (bool, error) {
+	start := time.Now()
+	ok, err := i.backend.Exists(ctx, key)
+	i.observe("exists", start, err)
+	return ok, err
+}
+
+func (i *instrumented) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	start := time.Now()
+	values, err := cache.GetMulti(ctx, i.backend, keys)
+	i.observe("get_multi", start, err)
+	return values, err
+}
+
+func (i *instrumented) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	start := time.Now()
+	err := cache.SetMulti(ctx, i.backend, items, ttl)
+	i.observe("set_multi", start, err)
+	return err
+}
+
+func (i *instrumented) DeleteMulti(ctx context.Context, keys []string) ([]string, error) {
+	start := time.Now()
+	deleted, err := cache.DeleteMulti(ctx, i.backend, keys)
+	i.observe("delete_multi", start, err)
+	return deleted, err
+}
+
+func (i *instrumented) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	backend, ok := i.backend.(cache.Incrementer)
+	if !ok {
+		return 0, errors.ErrUnsupported
+	}
+	start := time.Now()
+	n, err := backend.IncrBy(ctx, key, delta)
+	i.observe("incr", start, err)
+	return n, err
+}
+
+func (i *instrumented) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	backend, ok := i.backend.(cache.ConditionalSetter)
+	if !ok {
+		return false, errors.ErrUnsupported
+	}
+	start := time.Now()
+	stored, err := backend.SetNX(ctx, key, value, ttl)
+	i.observe("set_nx", start, err)
+	return stored, err
+}
+
+func (i *instrumented) Replace(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	backend, ok := i.backend.(cache.ConditionalSetter)
+	if !ok {
+		return false, errors.ErrUnsupported
+	}
+	start := time.Now()
+	stored, err := backend.Replace(ctx, key, value, ttl)
+	i.observe("replace", start, err)
+	return stored, err
+}
+
+func (i *instrumented) GetVersioned(ctx context.Context, key string) (interface{}, uint64, error) {
+	backend, ok := i.backend.(cache.ConditionalSetter)
+	if !ok {
+		return nil, 0, errors.ErrUnsupported
+	}
+	start := time.Now()
+	value, version, err := backend.GetVersioned(ctx, key)
+	i.observe("get_versioned", start, err)
+	return value, version, err
+}
+
+func (i *instrumented) CompareAndSwap(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error) {
+	backend, ok := i.backend.(cache.ConditionalSetter)
+	if !ok {
+		return 0, errors.ErrUnsupported
+	}
+	start := time.Now()
+	newVersion, err := backend.CompareAndSwap(ctx, key, value, version, ttl)
+	i.observe("compare_and_swap", start, err)
+	return newVersion, err
+}
+
+func (i *instrumented) Close() error {
+	return i.backend.Close()
+}
+
+// isMiss reports whether err only says a key was absent, or that it was not
+// in the state a conditional write expected.
+func isMiss(err error) bool {
+	return errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, cache.ErrVersionMismatch)
+}
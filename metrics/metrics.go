@@ -0,0 +1,289 @@
+// Package metrics exports cache server metrics in the Prometheus text
+// exposition format, without depending on a Prometheus client library.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// DefaultBuckets are the latency histogram bounds in seconds, from 100µs
+// for in-memory hits to 2.5s for Redis calls near their timeout.
+var DefaultBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Collector gathers the metrics of one cache backend and the HTTP server in
+// front of it.
+type Collector struct {
+	backend string
+	stats   cache.StatsReporter // May be nil
+
+	lock         sync.Mutex
+	opLatency    map[string]*histogram // By operation
+	opErrors     map[string]uint64     // By operation
+	httpLatency  map[httpKey]*histogram
+	httpRequests map[httpKey]uint64
+	inFlight     atomic.Int64
+}
+
+// httpKey identifies an HTTP route and its outcome.
+type httpKey struct {
+	method, route, code string
+}
+
+// New returns a Collector for the backend called name. If stats is not nil,
+// its Stats are exported on every scrape.
+func New(name string, stats cache.StatsReporter) *Collector {
+	return &Collector{
+		backend:      name,
+		stats:        stats,
+		opLatency:    make(map[string]*histogram),
+		opErrors:     make(map[string]uint64),
+		httpLatency:  make(map[httpKey]*histogram),
+		httpRequests: make(map[httpKey]uint64),
+	}
+}
+
+// ObserveOperation records a cache operation that took d. Errors other than
+// cache misses and version mismatches are counted as backend errors.
+func (c *Collector) ObserveOperation(op string, d time.Duration, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h, ok := c.opLatency[op]
+	if !ok {
+		h = newHistogram(DefaultBuckets)
+		c.opLatency[op] = h
+	}
+	h.observe(d.Seconds())
+	if err != nil && !isMiss(err) {
+		c.opErrors[op]++
+	}
+}
+
+// ObserveRequest records an HTTP request to route that took d.
+func (c *Collector) ObserveRequest(method, route string, code int, d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := httpKey{method: method, route: route, code: strconv.Itoa(code)}
+	c.httpRequests[key]++
+	latencyKey := httpKey{method: method, route: route}
+	h, ok := c.httpLatency[latencyKey]
+	if !ok {
+		h = newHistogram(DefaultBuckets)
+		c.httpLatency[latencyKey] = h
+	}
+	h.observe(d.Seconds())
+}
+
+// RequestStarted and RequestFinished track requests in flight.
+func (c *Collector) RequestStarted()  { c.inFlight.Add(1) }
+func (c *Collector) RequestFinished() { c.inFlight.Add(-1) }
+
+// WriteTo writes every metric to w in the Prometheus text format. Stats are
+// read from the backend with ctx; if that fails the other metrics are still
+// written and the error is returned.
+func (c *Collector) WriteTo(ctx context.Context, w io.Writer) error {
+	out := &writer{w: w}
+	backend := label{"backend", c.backend}
+
+	c.lock.Lock()
+	out.header("cache_operation_duration_seconds", "histogram", "Latency of cache backend operations.")
+	for _, op := range sortedKeys(c.opLatency) {
+		c.opLatency[op].write(out, "cache_operation_duration_seconds", backend, label{"operation", op})
+	}
+	out.header("cache_operation_errors_total", "counter", "Cache backend operations that failed, not counting misses.")
+	for _, op := range sortedKeys(c.opErrors) {
+		out.sample("cache_operation_errors_total", float64(c.opErrors[op]), backend, label{"operation", op})
+	}
+	out.header("cache_http_requests_total", "counter", "HTTP requests served, by route and status code.")
+	for _, key := range sortedHTTPKeys(c.httpRequests) {
+		out.sample("cache_http_requests_total", float64(c.httpRequests[key]), backend,
+			label{"method", key.method}, label{"route", key.route}, label{"code", key.code})
+	}
+	out.header("cache_http_request_duration_seconds", "histogram", "Latency of HTTP requests, by route.")
+	for _, key := range sortedHTTPKeys(c.httpLatency) {
+		c.httpLatency[key].write(out, "cache_http_request_duration_seconds", backend,
+			label{"method", key.method}, label{"route", key.route})
+	}
+	c.lock.Unlock()
+
+	out.header("cache_http_requests_in_flight", "gauge", "HTTP requests being served.")
+	out.sample("cache_http_requests_in_flight", float64(c.inFlight.Load()), backend)
+
+	var statsErr error
+	if c.stats != nil {
+		stats, err := c.stats.Stats(ctx)
+		if err != nil {
+			statsErr = fmt.Errorf("metrics: reading stats: %w", err)
+		} else {
+			writeStats(out, backend, stats)
+		}
+	}
+
+	if out.err != nil {
+		return out.err
+	}
+	return statsErr
+}
+
+// writeStats exports a backend's Stats.
+func writeStats(out *writer, backend label, stats cache.Stats) {
+	out.header("cache_hits_total", "counter", "Reads that found a value.")
+	out.sample("cache_hits_total", float64(stats.Hits), backend)
+	out.header("cache_misses_total", "counter", "Reads that found nothing.")
+	out.sample("cache_misses_total", float64(stats.Misses), backend)
+	out.header("cache_hit_ratio", "gauge", "Fraction of reads that found a value since the server started.")
+	out.sample("cache_hit_ratio", stats.HitRatio(), backend)
+	out.header("cache_sets_total", "counter", "Successful writes.")
+	out.sample("cache_sets_total", float64(stats.Sets), backend)
+	out.header("cache_deletes_total", "counter", "Delete calls.")
+	out.sample("cache_deletes_total", float64(stats.Deletes), backend)
+
+	out.header("cache_evictions_total", "counter", "Entries that left the cache, by reason.")
+	for _, e := range []struct {
+		reason cache.EvictionReason
+		n      uint64
+	}{
+		{cache.ReasonCapacity, stats.Evictions.Capacity},
+		{cache.ReasonExpired, stats.Evictions.Expired},
+		{cache.ReasonDeleted, stats.Evictions.Deleted},
+		{cache.ReasonCleared, stats.Evictions.Cleared},
+	} {
+		out.sample("cache_evictions_total", float64(e.n), backend, label{"reason", e.reason.String()})
+	}
+
+	out.header("cache_entries", "gauge", "Entries held now.")
+	out.sample("cache_entries", float64(stats.Entries), backend)
+	out.header("cache_bytes", "gauge", "Tracked size of the entries.")
+	out.sample("cache_bytes", float64(stats.Bytes), backend)
+
+	if stats.L1 != nil || stats.L2 != nil {
+		out.header("cache_level_hits_total", "counter", "Reads served by each level of a multi-level cache.")
+		out.sample("cache_level_hits_total", float64(stats.L1Hits), backend, label{"level", "l1"})
+		out.sample("cache_level_hits_total", float64(stats.L2Hits), backend, label{"level", "l2"})
+	}
+}
+
+// histogram counts observations into fixed buckets.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // counts[i] observations <= bounds[i] and > bounds[i-1]; the last is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// write emits the cumulative buckets, sum and count of h.
+func (h *histogram) write(out *writer, name string, labels ...label) {
+	var cumulative uint64
+	for i, n := range h.counts {
+		cumulative += n
+		le := math.Inf(1)
+		if i < len(h.bounds) {
+			le = h.bounds[i]
+		}
+		out.sample(name+"_bucket", float64(cumulative), append(labels, label{"le", formatFloat(le)})...)
+	}
+	out.sample(name+"_sum", h.sum, labels...)
+	out.sample(name+"_count", float64(h.count), labels...)
+}
+
+type label struct {
+	name, value string
+}
+
+// writer writes the text format, remembering the first error.
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (w *writer) printf(format string, args ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+func (w *writer) header(name, kind, help string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (w *writer) sample(name string, value float64, labels ...label) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l.name)
+			b.WriteString(`="`)
+			b.WriteString(labelEscaper.Replace(l.value))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	w.printf("%s %s\n", b.String(), formatFloat(value))
+}
+
+// labelEscaper escapes label values as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedHTTPKeys[V any](m map[httpKey]V) []httpKey {
+	keys := make([]httpKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.code < b.code
+	})
+	return keys
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so arbitrary paths
+// cannot create new series.
+const unmatchedRoute = "unmatched"
+
+// Middleware records the latency, status code and concurrency of every
+// request handled by a gin router.
+func (c *Collector) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c.RequestStarted()
+		defer c.RequestFinished()
+
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		c.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (c *Collector) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		if err := c.WriteTo(ctx.Request.Context(), ctx.Writer); err != nil {
+			// The status is already sent; report the failure as a comment.
+			ctx.Writer.WriteString("# error: " + strings.ReplaceAll(err.Error(), "\n", " ") + "\n")
+		}
+	}
+}
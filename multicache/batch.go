@@ -0,0 +1,149 @@
+package multicache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+var _ cache.Batcher = (*MultiCache)(nil)
+
+// GetMulti returns the values of the keys that are present in either level,
+// reading L2 once for all keys L1 misses. Values found in L2 are copied into
+// L1 as Get does, which asks L2 for each key's TTL.
+func (m *MultiCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values, err := cache.GetMulti(ctx, m.l1, keys)
+	if err != nil {
+		return nil, fmt.Errorf("l1: %w", err)
+	}
+	m.l1Hits.Add(uint64(len(values)))
+
+	var missing []string
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		// L1 may have evicted a write that is still queued for L2.
+		if write, ok := m.pendingWrite(key); ok {
+			m.l1Hits.Add(1)
+			values[key] = write.value
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	found, err := cache.GetMulti(ctx, m.l2, missing)
+	if err != nil {
+		return nil, fmt.Errorf("l2: %w", err)
+	}
+	m.l2Hits.Add(uint64(len(found)))
+	m.misses.Add(uint64(len(missing) - len(found)))
+	for key, value := range found {
+		values[key] = value
+		m.backfill(ctx, key, value)
+	}
+	return values, nil
+}
+
+// SetMulti stores every item according to the write policy, with one batch
+// per level.
+func (m *MultiCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	if err := m.setMulti(ctx, items, ttl); err != nil {
+		return err
+	}
+	m.sets.Add(uint64(len(items)))
+	return nil
+}
+
+// setMulti applies the write policy for SetMulti.
+func (m *MultiCache) setMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	switch m.policy {
+	case WriteAround:
+		if err := cache.SetMulti(ctx, m.l2, items, ttl); err != nil {
+			return fmt.Errorf("l2: %w", err)
+		}
+		return m.invalidateL1Multi(ctx, keys)
+
+	case WriteBack:
+		if err := cache.SetMulti(ctx, m.l1, items, ttl); err != nil {
+			return fmt.Errorf("l1: %w", err)
+		}
+		var deadline time.Time
+		if ttl > 0 {
+			deadline = time.Now().Add(ttl)
+		}
+		m.lock.Lock()
+		for key, value := range items {
+			m.pending[key] = pendingWrite{value: value, ttl: ttl, deadline: deadline}
+		}
+		m.lock.Unlock()
+		return nil
+	}
+
+	// WriteThrough. L2 goes first so L1 never holds a value L2 rejected.
+	if err := cache.SetMulti(ctx, m.l2, items, ttl); err != nil {
+		m.invalidateL1Multi(ctx, keys)
+		return fmt.Errorf("l2: %w", err)
+	}
+	if err := cache.SetMulti(ctx, m.l1, items, ttl); err != nil {
+		return fmt.Errorf("l1: %w", err)
+	}
+	return nil
+}
+
+// invalidateL1Multi drops keys from L1.
+func (m *MultiCache) invalidateL1Multi(ctx context.Context, keys []string) error {
+	if _, err := cache.DeleteMulti(ctx, m.l1, keys); err != nil {
+		return fmt.Errorf("l1: %w", err)
+	}
+	return nil
+}
+
+// DeleteMulti removes keys from both levels, including any queued writes,
+// and returns those that either level or the queue held.
+func (m *MultiCache) DeleteMulti(ctx context.Context, keys []string) ([]string, error) {
+	m.flushLock.Lock()
+	defer m.flushLock.Unlock()
+
+	m.deletes.Add(uint64(len(keys)))
+	present := make(map[string]bool)
+	m.lock.Lock()
+	for _, key := range keys {
+		if _, ok := m.pending[key]; ok {
+			present[key] = true
+			delete(m.pending, key)
+		}
+	}
+	m.lock.Unlock()
+
+	l1Deleted, err := cache.DeleteMulti(ctx, m.l1, keys)
+	if err != nil {
+		return nil, fmt.Errorf("l1: %w", err)
+	}
+	l2Deleted, err := cache.DeleteMulti(ctx, m.l2, keys)
+	if err != nil {
+		return nil, fmt.Errorf("l2: %w", err)
+	}
+	for _, key := range append(l1Deleted, l2Deleted...) {
+		present[key] = true
+	}
+
+	var deleted []string
+	for _, key := range keys {
+		if present[key] {
+			deleted = append(deleted, key)
+			delete(present, key) // Report repeated keys once
+		}
+	}
+	return deleted, nil
+}
@@ -1,171 +1,462 @@
 package multicache
 
 import (
-	"net/http"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
-	"github.com/Devisree146/Go_project-library.git/in_memory"
-	"github.com/Devisree146/Go_project-library.git/redis_cache"
 
-	"github.com/gin-gonic/gin"
+	"github.com/Devisree146/Go_project-library.git/cache"
 )
 
-var cacheInMemory *in_memory.InMemoryCache
-var cacheRedis *redis_cache.Cache
+// WritePolicy decides how a write reaches the two levels of a MultiCache.
+type WritePolicy int
 
-func init() {
-	cacheInMemory = in_memory.NewInMemoryCache(3, 5*time.Minute) // Initialize with TTL
-	cacheRedis = redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
+const (
+	// WriteThrough writes to L2 and then L1 before returning.
+	WriteThrough WritePolicy = iota
+	// WriteAround writes to L2 only and drops any stale copy from L1; the
+	// next read backfills L1.
+	WriteAround
+	// WriteBack writes to L1 and queues the write for L2, which is flushed
+	// in the background every flush interval and on Flush or Close.
+	WriteBack
+)
+
+// DefaultFlushInterval is how often queued WriteBack writes reach L2.
+const DefaultFlushInterval = time.Second
+
+// MultiCache serves reads from a fast first-level cache (usually in-memory)
+// and falls back to a second-level cache (usually Redis) on a miss, copying
+// the value back into L1 for subsequent reads.
+type MultiCache struct {
+	l1            cache.Cache
+	l2            cache.Cache
+	policy        WritePolicy
+	flushInterval time.Duration
+
+	// pending holds WriteBack writes that have not reached L2 yet.
+	lock    sync.Mutex
+	pending map[string]pendingWrite
+	// flushLock keeps deletes from racing a flush that could write a
+	// deleted key back into L2.
+	flushLock sync.Mutex
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+
+	l1Hits, l2Hits, misses, sets, deletes atomic.Uint64
+
+	loads cache.Group[string, interface{}] // Coalesces GetOrLoad misses
 }
 
-// Example usage in your API handlers or other functions
-func ExampleHandler(c *gin.Context) {
-	value, err := cacheInMemory.Get("some_key")
-	if err != nil {
-		if err == in_memory.ErrCacheMiss {
-			// Handle cache miss for in-memory cache
-			// Example response
-			c.JSON(http.StatusNotFound, gin.H{"error": "Key not found in in-memory cache"})
-			return
+// pendingWrite is a queued WriteBack write.
+type pendingWrite struct {
+	value    interface{}
+	ttl      time.Duration
+	deadline time.Time // Zero unless ttl is positive
+}
+
+var (
+	_ cache.Cache         = (*MultiCache)(nil)
+	_ cache.TTLer         = (*MultiCache)(nil)
+	_ cache.StatsReporter = (*MultiCache)(nil)
+	_ cache.Loader        = (*MultiCache)(nil)
+)
+
+// Option configures optional MultiCache behaviour in New.
+type Option func(*MultiCache)
+
+// WithWritePolicy sets how writes reach the two levels. The default is WriteThrough.
+func WithWritePolicy(policy WritePolicy) Option {
+	return func(m *MultiCache) {
+		m.policy = policy
+	}
+}
+
+// WithFlushInterval sets how often WriteBack writes are flushed to L2. A
+// zero or negative d selects DefaultFlushInterval.
+func WithFlushInterval(d time.Duration) Option {
+	return func(m *MultiCache) {
+		if d <= 0 {
+			d = DefaultFlushInterval
 		}
-		// Handle other errors
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving key from in-memory cache"})
-		return
+		m.flushInterval = d
 	}
+}
 
-	// Continue processing with the retrieved value
-	c.JSON(http.StatusOK, gin.H{"key": "some_key", "value": value})
+// WithNegativeTTL makes GetOrLoad remember, for d, keys whose loader
+// returned cache.ErrCacheMiss. Zero, the default, calls the loader on every miss.
+func WithNegativeTTL(d time.Duration) Option {
+	return func(m *MultiCache) {
+		m.loads.NegativeTTL = d
+	}
 }
 
-func SetupRouter() *gin.Engine {
-	router := gin.Default()
+// New returns a MultiCache that layers l1 in front of l2.
+func New(l1, l2 cache.Cache, opts ...Option) *MultiCache {
+	m := &MultiCache{
+		l1:            l1,
+		l2:            l2,
+		policy:        WriteThrough,
+		flushInterval: DefaultFlushInterval,
+		pending:       make(map[string]pendingWrite),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
 
-	// Example middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	if notifier, ok := l2.(cache.Notifier); ok {
+		notifier.OnEvict(m.l2Evicted)
+	}
 
-	router.POST("/cache", func(c *gin.Context) {
-		var data struct {
-			Key   string `json:"key"`
-			Value int    `json:"value"`
-			TTL   string `json:"ttl"` // Assuming you may receive it, but not mandatory
-		}
-		if err := c.ShouldBindJSON(&data); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-			return
-		}
+	if m.policy == WriteBack {
+		m.wg.Add(1)
+		go m.flushLoop()
+	}
+	return m
+}
 
-		// Handle TTL logic
-		var ttlDuration time.Duration
-		if data.TTL != "" {
-			var err error
-			ttlDuration, err = time.ParseDuration(data.TTL)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TTL format"})
-				return
-			}
-		} else {
-			// Use default TTL here if TTL is not provided in the request
-			ttlDuration = 5 * time.Minute
+// l2Evicted keeps L1 from serving a key that L2 evicted or found expired.
+// Deletes are already applied to both levels by Delete and DeleteAll. Keys
+// with a queued WriteBack write are left alone, since L1 holds a newer value.
+func (m *MultiCache) l2Evicted(key string, _ interface{}, reason cache.EvictionReason) {
+	if reason != cache.ReasonCapacity && reason != cache.ReasonExpired {
+		return
+	}
+	if _, ok := m.pendingWrite(key); ok {
+		return
+	}
+	m.invalidateL1(context.Background(), key)
+}
+
+// Get returns the value from L1, or from L2 on an L1 miss. A value found in
+// L2 is copied into L1 for no longer than it has left to live in L2.
+func (m *MultiCache) Get(ctx context.Context, key string) (interface{}, error) {
+	value, err := m.l1.Get(ctx, key)
+	if err == nil {
+		m.l1Hits.Add(1)
+		return value, nil
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, fmt.Errorf("l1: %w", err)
+	}
+
+	// L1 may have evicted a write that is still queued for L2.
+	if write, ok := m.pendingWrite(key); ok {
+		m.l1Hits.Add(1)
+		return write.value, nil
+	}
+
+	value, err = m.l2.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			m.misses.Add(1)
+			return nil, err
 		}
+		return nil, fmt.Errorf("l2: %w", err)
+	}
 
-		// Convert value to interface{} type before setting in cache
-		value := interface{}(data.Value)
+	m.l2Hits.Add(1)
+	m.backfill(ctx, key, value)
+	return value, nil
+}
 
-		err := cacheInMemory.Set(data.Key, value)
+// backfill copies a value read from L2 into L1. It is best effort: a failure
+// only costs another L2 read later.
+func (m *MultiCache) backfill(ctx context.Context, key string, value interface{}) {
+	var ttl time.Duration // L1's default TTL
+	if ttler, ok := m.l2.(cache.TTLer); ok {
+		remaining, err := ttler.TTL(ctx, key)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set key in in-memory cache"})
 			return
 		}
+		if remaining > 0 {
+			ttl = remaining
+		}
+	}
+	m.l1.Set(ctx, key, value, ttl)
+}
 
-		// Handling TTL logic separately if needed
-		time.AfterFunc(ttlDuration, func() {
-			cacheInMemory.Delete(data.Key)
-		})
+// GetOrLoad returns the value from either level. On a miss in both it calls
+// loader, once for all concurrent callers with the same key, and stores the
+// result with Set and the levels' default TTLs. If loader returns an error
+// wrapping cache.ErrCacheMiss, GetOrLoad returns cache.ErrCacheMiss.
+func (m *MultiCache) GetOrLoad(ctx context.Context, key string, loader func() (interface{}, error)) (interface{}, error) {
+	return m.loads.GetOrLoad(ctx, key,
+		func() (interface{}, error) { return m.Get(ctx, key) },
+		loader,
+		func(value interface{}) error { return m.Set(ctx, key, value, 0) },
+	)
+}
 
-		// Perform type assertion to int before setting in Redis cache
-		intValue, ok := value.(int)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assert value to int"})
-			return
+// Set stores value under key according to the write policy.
+func (m *MultiCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := m.set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	m.sets.Add(1)
+	return nil
+}
+
+// set applies the write policy for Set.
+func (m *MultiCache) set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	switch m.policy {
+	case WriteAround:
+		if err := m.l2.Set(ctx, key, value, ttl); err != nil {
+			return fmt.Errorf("l2: %w", err)
 		}
+		return m.invalidateL1(ctx, key)
 
-		err = cacheRedis.Set(data.Key, intValue, ttlDuration)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set key in Redis cache"})
-			return
+	case WriteBack:
+		if err := m.l1.Set(ctx, key, value, ttl); err != nil {
+			return fmt.Errorf("l1: %w", err)
+		}
+		write := pendingWrite{value: value, ttl: ttl}
+		if ttl > 0 {
+			write.deadline = time.Now().Add(ttl)
 		}
+		m.lock.Lock()
+		m.pending[key] = write
+		m.lock.Unlock()
+		return nil
+	}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Key set successfully"})
-	})
+	// WriteThrough. L2 goes first so L1 never holds a value L2 rejected.
+	if err := m.l2.Set(ctx, key, value, ttl); err != nil {
+		m.invalidateL1(ctx, key)
+		return fmt.Errorf("l2: %w", err)
+	}
+	if err := m.l1.Set(ctx, key, value, ttl); err != nil {
+		return fmt.Errorf("l1: %w", err)
+	}
+	return nil
+}
 
-	router.GET("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
+// invalidateL1 drops key from L1, ignoring misses.
+func (m *MultiCache) invalidateL1(ctx context.Context, key string) error {
+	if err := m.l1.Delete(ctx, key); err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return fmt.Errorf("l1: %w", err)
+	}
+	return nil
+}
+
+// Delete removes key from both levels, including any queued write. It only
+// reports ErrCacheMiss when neither level held the key.
+func (m *MultiCache) Delete(ctx context.Context, key string) error {
+	m.flushLock.Lock()
+	defer m.flushLock.Unlock()
+
+	m.deletes.Add(1)
+	m.lock.Lock()
+	_, wasPending := m.pending[key]
+	delete(m.pending, key)
+	m.lock.Unlock()
+
+	l1Err := m.l1.Delete(ctx, key)
+	if l1Err != nil && !errors.Is(l1Err, cache.ErrCacheMiss) {
+		return fmt.Errorf("l1: %w", l1Err)
+	}
+
+	l2Err := m.l2.Delete(ctx, key)
+	if l2Err != nil && !errors.Is(l2Err, cache.ErrCacheMiss) {
+		return fmt.Errorf("l2: %w", l2Err)
+	}
+
+	if l1Err != nil && l2Err != nil && !wasPending {
+		return cache.ErrCacheMiss
+	}
+	return nil
+}
+
+// DeleteAll empties both levels and discards queued writes.
+func (m *MultiCache) DeleteAll(ctx context.Context) error {
+	m.flushLock.Lock()
+	defer m.flushLock.Unlock()
+
+	m.lock.Lock()
+	m.pending = make(map[string]pendingWrite)
+	m.lock.Unlock()
+
+	if err := m.l1.DeleteAll(ctx); err != nil {
+		return fmt.Errorf("l1: %w", err)
+	}
+	if err := m.l2.DeleteAll(ctx); err != nil {
+		return fmt.Errorf("l2: %w", err)
+	}
+	return nil
+}
+
+// Keys returns the union of the keys held by both levels.
+func (m *MultiCache) Keys(ctx context.Context) ([]string, error) {
+	l1Keys, err := m.l1.Keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("l1: %w", err)
+	}
+	l2Keys, err := m.l2.Keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("l2: %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(l1Keys)+len(l2Keys))
+	keys := make([]string, 0, len(l1Keys)+len(l2Keys))
+	for _, key := range append(l1Keys, l2Keys...) {
+		if _, ok := seen[key]; ok {
+			continue
 		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Exists reports whether either level holds key.
+func (m *MultiCache) Exists(ctx context.Context, key string) (bool, error) {
+	ok, err := m.l1.Exists(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("l1: %w", err)
+	}
+	if ok {
+		return true, nil
+	}
+	if _, ok := m.pendingWrite(key); ok {
+		return true, nil
+	}
+
+	ok, err = m.l2.Exists(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("l2: %w", err)
+	}
+	return ok, nil
+}
+
+// TTL returns the time key has left in L2, the level of record.
+func (m *MultiCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttler, ok := m.l2.(cache.TTLer)
+	if !ok {
+		return 0, errors.New("multicache: L2 cache does not report TTLs")
+	}
+	return ttler.TTL(ctx, key)
+}
+
+// Stats returns the MultiCache's own counters, with hits split by the level
+// that served them (queued WriteBack writes count as L1), and the stats of
+// each level that reports them. Sizes and evictions are only per level.
+func (m *MultiCache) Stats(ctx context.Context) (cache.Stats, error) {
+	l1Hits, l2Hits := m.l1Hits.Load(), m.l2Hits.Load()
+	stats := cache.Stats{
+		Hits:    l1Hits + l2Hits,
+		Misses:  m.misses.Load(),
+		Sets:    m.sets.Load(),
+		Deletes: m.deletes.Load(),
+		L1Hits:  l1Hits,
+		L2Hits:  l2Hits,
+	}
 
-		value, err := cacheInMemory.Get(key)
+	if reporter, ok := m.l1.(cache.StatsReporter); ok {
+		l1, err := reporter.Stats(ctx)
 		if err != nil {
-			if err == in_memory.ErrCacheMiss {
-				value, err = cacheRedis.Get(key)
-				if err != nil {
-					if err == redis_cache.ErrCacheMiss {
-						c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
-					} else {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving key from Redis cache"})
-					}
-					return
-				}
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving key from in-memory cache"})
-				return
-			}
+			return cache.Stats{}, fmt.Errorf("l1: %w", err)
+		}
+		stats.L1 = &l1
+	}
+	if reporter, ok := m.l2.(cache.StatsReporter); ok {
+		l2, err := reporter.Stats(ctx)
+		if err != nil {
+			return cache.Stats{}, fmt.Errorf("l2: %w", err)
 		}
+		stats.L2 = &l2
+	}
+	return stats, nil
+}
 
-		c.JSON(http.StatusOK, gin.H{"key": key, "value": value})
-	})
+// pendingWrite returns the queued, unexpired WriteBack write for key.
+func (m *MultiCache) pendingWrite(key string) (pendingWrite, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 
-	router.DELETE("/cache", func(c *gin.Context) {
-		key := c.Query("key")
-		if key == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Key not provided"})
-			return
-		}
+	write, ok := m.pending[key]
+	if ok && !write.deadline.IsZero() && !write.deadline.After(time.Now()) {
+		return pendingWrite{}, false
+	}
+	return write, ok
+}
 
-		err := cacheInMemory.Delete(key)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting key from in-memory cache"})
-			return
+// Flush writes every queued WriteBack write to L2. Writes that fail are
+// queued again unless a newer write for the same key arrived meanwhile.
+func (m *MultiCache) Flush(ctx context.Context) error {
+	m.flushLock.Lock()
+	defer m.flushLock.Unlock()
+
+	m.lock.Lock()
+	batch := m.pending
+	m.pending = make(map[string]pendingWrite)
+	m.lock.Unlock()
+
+	var errs []error
+	now := time.Now()
+	for key, write := range batch {
+		ttl := write.ttl
+		if !write.deadline.IsZero() {
+			ttl = write.deadline.Sub(now)
+			if ttl <= 0 {
+				continue // Expired before it was flushed
+			}
 		}
 
-		err = cacheRedis.Delete(key)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting key from Redis cache"})
-			return
+		if err := m.l2.Set(ctx, key, write.value, ttl); err != nil {
+			errs = append(errs, fmt.Errorf("l2: %s: %w", key, err))
+			m.lock.Lock()
+			if _, newer := m.pending[key]; !newer {
+				m.pending[key] = write
+			}
+			m.lock.Unlock()
 		}
+	}
+	return errors.Join(errs...)
+}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Key deleted successfully"})
-	})
+// flushLoop flushes WriteBack writes until Close is called.
+func (m *MultiCache) flushLoop() {
+	defer m.wg.Done()
 
-	router.GET("/cache/all", func(c *gin.Context) {
-		cachedKeysInMemory := cacheInMemory.GetAllKeys() // Assuming GetAllKeys() retrieves all keys
-		cachedKeysRedis, err := cacheRedis.GetAllKeys()  // Assuming GetAllKeys() retrieves all keys
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keys from Redis cache"})
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Flush(context.Background())
+		case <-m.done:
 			return
 		}
+	}
+}
 
-		allCachedKeys := append(cachedKeysInMemory, cachedKeysRedis...)
-
-		c.JSON(http.StatusOK, gin.H{"keys": allCachedKeys})
-	})
+// Close stops the background flusher, flushes any queued WriteBack writes
+// and then closes both levels.
+// Calling Close more than once returns the first call's result.
+func (m *MultiCache) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+		m.wg.Wait()
 
-	router.DELETE("/cache/all", func(c *gin.Context) {
-		cacheInMemory.DeleteAll()
-		cacheRedis.DeleteAll()
-		c.JSON(http.StatusOK, gin.H{"message": "All keys deleted successfully"})
+		var errs []error
+		if err := m.Flush(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+		if err := m.l1.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("l1: %w", err))
+		}
+		if err := m.l2.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("l2: %w", err))
+		}
+		m.closeErr = errors.Join(errs...)
 	})
-
-	return router
+	return m.closeErr
 }
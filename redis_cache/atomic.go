@@ -0,0 +1,153 @@
+package redis_cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// IncrBy adds delta to the integer stored under key with Redis INCRBY and
+// returns the result, recording the write in the LRU index. A missing key
+// is created from zero without an expiry; an existing key keeps its TTL.
+// Values must be stored as decimal text, as JSONCodec stores integers;
+// anything else fails with cache.ErrNotInteger.
+func (c *Cache) IncrBy(key string, delta int64) (int64, error) {
+	return c.IncrByCtx(context.Background(), key, delta)
+}
+
+// IncrByCtx is like IncrBy but honours the deadline and cancellation of ctx.
+func (c *Cache) IncrByCtx(ctx context.Context, key string, delta int64) (int64, error) {
+	return c.incrBy(ctx, key, delta, 0)
+}
+
+// Incr adds one to the integer stored under key. See IncrBy.
+func (c *Cache) Incr(key string) (int64, error) {
+	return c.IncrBy(key, 1)
+}
+
+// Decr subtracts one from the integer stored under key. See IncrBy.
+func (c *Cache) Decr(key string) (int64, error) {
+	return c.IncrBy(key, -1)
+}
+
+// incrBy runs incrScript, creating missing keys with ttl.
+func (c *Cache) incrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	wantValues := 0
+	if c.hasHooks() {
+		wantValues = 1
+	}
+	reply, err := incrScript.Run(ctx, c.client, c.scriptKeys([]string{key}),
+		delta, ttlMillis(ttl), c.maxSize, wantValues).Slice()
+	if err != nil {
+		// INCRBY's error reaches us inside the script's error message.
+		if strings.Contains(err.Error(), "not an integer") {
+			return 0, cache.ErrNotInteger
+		}
+		return 0, cache.WrapTimeout(err)
+	}
+
+	result, _ := reply[0].(int64)
+	c.counters.sets.Add(1)
+	dropped, _ := reply[1].([]interface{})
+	c.notifyDropped(dropped)
+	return result, nil
+}
+
+// SetNX encodes and stores value only if key is absent, and reports whether
+// it did. ttl is as for SetValue.
+func (c *Cache) SetNX(key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.SetNXCtx(context.Background(), key, value, ttl)
+}
+
+// SetNXCtx is like SetNX but honours the deadline and cancellation of ctx.
+func (c *Cache) SetNXCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.setIf(ctx, key, value, ttl, "NX")
+}
+
+// Replace encodes and stores value only if key is present, and reports
+// whether it did. ttl is as for SetValue.
+func (c *Cache) Replace(key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.ReplaceCtx(context.Background(), key, value, ttl)
+}
+
+// ReplaceCtx is like Replace but honours the deadline and cancellation of ctx.
+func (c *Cache) ReplaceCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.setIf(ctx, key, value, ttl, "XX")
+}
+
+// setIf stores value if condition holds and reports whether it did.
+func (c *Cache) setIf(ctx context.Context, key string, value interface{}, ttl time.Duration, condition string) (bool, error) {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	status, _, err := c.set(ctx, key, data, ttl, condition, 0)
+	return status == 1, err
+}
+
+// GetValueVersioned is like GetValue but also returns the key's version,
+// which changes on every write made through a Cache with this prefix. Keys
+// written by other clients have version 0.
+func (c *Cache) GetValueVersioned(key string, dst interface{}) (uint64, error) {
+	return c.GetValueVersionedCtx(context.Background(), key, dst)
+}
+
+// GetValueVersionedCtx is like GetValueVersioned but honours the deadline
+// and cancellation of ctx.
+func (c *Cache) GetValueVersionedCtx(ctx context.Context, key string, dst interface{}) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	reply, err := getScript.Run(ctx, c.client, c.scriptKeys([]string{key}), 1).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.counters.misses.Add(1)
+			return 0, ErrCacheMiss
+		}
+		return 0, cache.WrapTimeout(err)
+	}
+
+	pair, ok := reply.([]interface{})
+	if !ok {
+		// The script found the key in the LRU index but its value had expired.
+		c.counters.misses.Add(1)
+		c.counters.evicted(cache.ReasonExpired, 1)
+		c.notify(c.key(key), nil, cache.ReasonExpired)
+		return 0, ErrCacheMiss
+	}
+	c.counters.hits.Add(1)
+	data, _ := pair[0].(string)
+	version, _ := pair[1].(int64)
+	return uint64(version), c.codec.Unmarshal([]byte(data), dst)
+}
+
+// CompareAndSwap encodes and stores value only if key still has version,
+// and returns the new version. It fails with ErrCacheMiss if key is absent
+// and with cache.ErrVersionMismatch if the key was written since.
+func (c *Cache) CompareAndSwap(key string, value interface{}, version uint64, ttl time.Duration) (uint64, error) {
+	return c.CompareAndSwapCtx(context.Background(), key, value, version, ttl)
+}
+
+// CompareAndSwapCtx is like CompareAndSwap but honours the deadline and
+// cancellation of ctx.
+func (c *Cache) CompareAndSwapCtx(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error) {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	status, newVersion, err := c.set(ctx, key, data, ttl, "CAS", version)
+	switch {
+	case err != nil:
+		return 0, err
+	case status == 0:
+		return 0, ErrCacheMiss
+	case status < 0:
+		return 0, cache.ErrVersionMismatch
+	}
+	return newVersion, nil
+}
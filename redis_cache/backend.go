@@ -0,0 +1,159 @@
+package redis_cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// Backend adapts a Redis Cache to the cache.Cache interface.
+type Backend struct {
+	cache *Cache
+}
+
+var (
+	_ cache.Cache         = (*Backend)(nil)
+	_ cache.TTLer         = (*Backend)(nil)
+	_ cache.Notifier      = (*Backend)(nil)
+	_ cache.StatsReporter = (*Backend)(nil)
+	_ cache.Loader        = (*Backend)(nil)
+	_ cache.Batcher       = (*Backend)(nil)
+
+	_ cache.Incrementer       = (*Backend)(nil)
+	_ cache.ConditionalSetter = (*Backend)(nil)
+)
+
+// NewBackend wraps c so it can be used wherever a cache.Cache is expected.
+func NewBackend(c *Cache) *Backend {
+	return &Backend{cache: c}
+}
+
+// Get returns the value stored under key.
+func (b *Backend) Get(ctx context.Context, key string) (interface{}, error) {
+	var value interface{}
+	if err := b.cache.GetValueCtx(ctx, key, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set stores value under key for ttl, or for the cache's default TTL if ttl
+// is zero.
+// A negative ttl stores the key without an expiry.
+func (b *Backend) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return b.cache.SetValueCtx(ctx, key, value, b.cache.cacheTTL(ttl))
+}
+
+// GetMulti returns the values of the keys that are present in one round trip.
+func (b *Backend) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	return GetMultiAsCtx[interface{}](ctx, b.cache, keys)
+}
+
+// SetMulti stores every item in one round trip. ttl follows the rules of Set.
+func (b *Backend) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	return b.cache.SetMultiCtx(ctx, items, b.cache.cacheTTL(ttl))
+}
+
+// DeleteMulti removes keys in one round trip and returns those that were present.
+func (b *Backend) DeleteMulti(ctx context.Context, keys []string) ([]string, error) {
+	return b.cache.DeleteMultiCtx(ctx, keys)
+}
+
+// IncrBy adds delta to the integer stored under key. A missing key is
+// created with the cache's default TTL.
+func (b *Backend) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return b.cache.incrBy(ctx, key, delta, b.cache.defaultTTL)
+}
+
+// SetNX stores value only if key is absent, and reports whether it did.
+func (b *Backend) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return b.cache.SetNXCtx(ctx, key, value, b.cache.cacheTTL(ttl))
+}
+
+// Replace stores value only if key is present, and reports whether it did.
+func (b *Backend) Replace(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return b.cache.ReplaceCtx(ctx, key, value, b.cache.cacheTTL(ttl))
+}
+
+// GetVersioned returns the value stored under key and its version.
+func (b *Backend) GetVersioned(ctx context.Context, key string) (interface{}, uint64, error) {
+	var value interface{}
+	version, err := b.cache.GetValueVersionedCtx(ctx, key, &value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, version, nil
+}
+
+// CompareAndSwap stores value only if key still has version.
+func (b *Backend) CompareAndSwap(ctx context.Context, key string, value interface{}, version uint64, ttl time.Duration) (uint64, error) {
+	return b.cache.CompareAndSwapCtx(ctx, key, value, version, b.cache.cacheTTL(ttl))
+}
+
+// cacheTTL converts a cache.Cache ttl, where zero means the default and a
+// negative value means no expiry, to the Cache's, where zero means no expiry.
+func (c *Cache) cacheTTL(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		return c.defaultTTL
+	}
+	return max(ttl, 0)
+}
+
+// Delete removes key from Redis.
+func (b *Backend) Delete(ctx context.Context, key string) error {
+	return b.cache.DeleteCtx(ctx, key)
+}
+
+// GetOrLoad returns the value stored under key, filling a miss from loader.
+// A loaded value is returned as loader produced it; later reads return it
+// as decoded by the codec. See the package-level GetOrLoad.
+func (b *Backend) GetOrLoad(ctx context.Context, key string, loader func() (interface{}, error)) (interface{}, error) {
+	return GetOrLoad[interface{}](ctx, b.cache, key, loader)
+}
+
+// DeleteAll removes every key in the cache's namespace.
+func (b *Backend) DeleteAll(ctx context.Context) error {
+	return b.cache.DeleteAllCtx(ctx)
+}
+
+// Keys returns all keys in the cache's namespace.
+func (b *Backend) Keys(ctx context.Context) ([]string, error) {
+	return b.cache.GetAllKeysCtx(ctx)
+}
+
+// Exists reports whether key is present in Redis.
+func (b *Backend) Exists(ctx context.Context, key string) (bool, error) {
+	return b.cache.ExistsCtx(ctx, key)
+}
+
+// TTL returns the time left before key expires, or a negative duration if it
+// never does.
+func (b *Backend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return b.cache.TTLCtx(ctx, key)
+}
+
+// OnEvict registers fn to be called when a key leaves the cache. The value is
+// decoded with the cache's codec, and is nil when Redis no longer holds it or
+// it cannot be decoded. See Cache.OnEvict.
+func (b *Backend) OnEvict(fn func(key string, value interface{}, reason cache.EvictionReason)) {
+	b.cache.OnEvict(func(key string, data []byte, reason cache.EvictionReason) {
+		var value interface{}
+		if data != nil {
+			if err := b.cache.codec.Unmarshal(data, &value); err != nil {
+				value = nil
+			}
+		}
+		fn(key, value, reason)
+	})
+}
+
+// Stats returns the cache's counters and the number of keys it tracks.
+func (b *Backend) Stats(ctx context.Context) (cache.Stats, error) {
+	return b.cache.StatsCtx(ctx)
+}
+
+// Close closes the Redis client.
+func (b *Backend) Close() error {
+	return b.cache.Close()
+}
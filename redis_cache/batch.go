@@ -0,0 +1,197 @@
+package redis_cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// multiBatchSize bounds the keys sent to one script call, keeping the
+// script's MGET within Lua's stack limit. Larger batches take one round trip
+// per multiBatchSize keys.
+const multiBatchSize = 1000
+
+// chunks splits n items into ranges of at most multiBatchSize.
+func chunks(n int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += multiBatchSize {
+		if err := fn(start, min(start+multiBatchSize, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scriptKeys returns the Redis keys for keys followed by their shard's LRU
+// index, access clock and versions hash, as the scripts expect. The keys
+// must all belong to one shard; see byShard.
+func (c *Cache) scriptKeys(keys []string) []string {
+	redisKeys := make([]string, 0, len(keys)+3)
+	for _, key := range keys {
+		redisKeys = append(redisKeys, c.key(key))
+	}
+	sh := c.shardFor(keys[0])
+	return append(redisKeys, sh.lruKey, sh.clockKey, sh.versionKey)
+}
+
+// getMulti returns the encoded values of the keys that are present, reading
+// them and recording the accesses in one round trip.
+func (c *Cache) getMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	values := make(map[string][]byte, len(keys))
+	for _, group := range c.byShard(keys) {
+		err := chunks(len(group), func(start, end int) error {
+			batch := group[start:end]
+			reply, err := mgetScript.Run(ctx, c.client, c.scriptKeys(batch)).Slice()
+			if err != nil {
+				return cache.WrapTimeout(err)
+			}
+			for i, key := range batch {
+				switch value := reply[i].(type) {
+				case string:
+					c.counters.hits.Add(1)
+					values[key] = []byte(value)
+				case int64:
+					// The key was in the LRU index but its value had expired.
+					c.counters.misses.Add(1)
+					c.counters.evicted(cache.ReasonExpired, 1)
+					c.notify(c.key(key), nil, cache.ReasonExpired)
+				default:
+					c.counters.misses.Add(1)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// GetMultiAs returns the values of the keys that are present, decoded as T,
+// in one round trip. Missing keys are left out.
+func GetMultiAs[T any](c *Cache, keys []string) (map[string]T, error) {
+	return GetMultiAsCtx[T](context.Background(), c, keys)
+}
+
+// GetMultiAsCtx is like GetMultiAs but honours the deadline and cancellation of ctx.
+func GetMultiAsCtx[T any](ctx context.Context, c *Cache, keys []string) (map[string]T, error) {
+	data, err := c.getMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]T, len(data))
+	for key, encoded := range data {
+		var value T
+		if err := c.codec.Unmarshal(encoded, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// SetMulti encodes and stores every item with the same ttl in one round
+// trip, evicting least recently used keys once the whole batch is stored.
+func (c *Cache) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	return c.SetMultiCtx(context.Background(), items, ttl)
+}
+
+// SetMultiCtx is like SetMulti but honours the deadline and cancellation of ctx.
+func (c *Cache) SetMultiCtx(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	// Encode everything first so a bad value stores nothing.
+	keys := make([]string, 0, len(items))
+	encoded := make(map[string][]byte, len(items))
+	for key, value := range items {
+		data, err := c.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		encoded[key] = data
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	wantValues := 0
+	if c.hasHooks() {
+		wantValues = 1
+	}
+	for _, group := range c.byShard(keys) {
+		err := chunks(len(group), func(start, end int) error {
+			args := []interface{}{ttlMillis(ttl), c.maxSize, wantValues}
+			for _, key := range group[start:end] {
+				args = append(args, encoded[key])
+			}
+			dropped, err := msetScript.Run(ctx, c.client, c.scriptKeys(group[start:end]), args...).Slice()
+			if err != nil {
+				return cache.WrapTimeout(err)
+			}
+			c.counters.sets.Add(uint64(end - start))
+			c.notifyDropped(dropped)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMulti removes keys in one round trip and returns those that were present.
+func (c *Cache) DeleteMulti(keys []string) ([]string, error) {
+	return c.DeleteMultiCtx(context.Background(), keys)
+}
+
+// DeleteMultiCtx is like DeleteMulti but honours the deadline and cancellation of ctx.
+func (c *Cache) DeleteMultiCtx(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	// Values are only fetched for the OnEvict hooks.
+	hooks := c.hasHooks()
+	gets := make([]*redis.StringCmd, len(keys))
+	dels := make([]*redis.IntCmd, len(keys))
+	cmds, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			if hooks {
+				gets[i] = pipe.Get(ctx, c.key(key))
+			}
+			dels[i] = pipe.Del(ctx, c.key(key))
+		}
+		for sh, group := range c.byShard(keys) {
+			members := make([]interface{}, len(group))
+			fields := make([]string, len(group))
+			for i, key := range group {
+				members[i] = c.key(key)
+				fields[i] = c.key(key)
+			}
+			pipe.ZRem(ctx, sh.lruKey, members...)
+			pipe.HDel(ctx, sh.versionKey, fields...)
+		}
+		return nil
+	})
+	if err := pipelineErr(err, cmds); err != nil {
+		return nil, cache.WrapTimeout(err)
+	}
+
+	c.counters.deletes.Add(uint64(len(keys)))
+	var deleted []string
+	for i, key := range keys {
+		if dels[i].Val() != 1 {
+			continue
+		}
+		deleted = append(deleted, key)
+		c.counters.evicted(cache.ReasonDeleted, 1)
+		if hooks {
+			c.notify(c.key(key), []byte(gets[i].Val()), cache.ReasonDeleted)
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,103 @@
+package redis_cache
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ClientOptions describes how to connect to Redis. Addr names a single
+// server unless MasterName and SentinelAddrs select a Sentinel-managed
+// primary or ClusterAddrs a Redis Cluster. Zero values leave go-redis's
+// defaults in place.
+type ClientOptions struct {
+	Addr string
+
+	MasterName    string
+	SentinelAddrs []string
+	ClusterAddrs  []string // Seed nodes; DB must be 0
+
+	// Username selects a Redis 6 ACL user. Empty authenticates Password as
+	// the default user, as with requirepass.
+	Username string
+	Password string
+	DB       int
+
+	// TLSConfig enables TLS, including client certificates. It is used for
+	// the Sentinels as well as the data nodes.
+	TLSConfig *tls.Config
+
+	PoolSize     int // Connections per node; default 10 per CPU
+	MinIdleConns int
+
+	DialTimeout  time.Duration // Default 5s
+	ReadTimeout  time.Duration // Default 3s
+	WriteTimeout time.Duration // Default ReadTimeout
+
+	// MaxRetries is how many times a failed command is retried, with a
+	// backoff between MinRetryBackoff and MaxRetryBackoff. The default is
+	// 3; -1 disables retries.
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// NewWithOptions connects to the Redis described by client. See
+// NewWithClient for the cache itself.
+func NewWithOptions(client ClientOptions, maxSize int, opts ...Option) *Cache {
+	return NewWithClient(client.newClient(), maxSize, opts...)
+}
+
+// newClient returns a client for the topology o describes.
+func (o ClientOptions) newClient() redis.UniversalClient {
+	switch {
+	case len(o.ClusterAddrs) > 0:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:           o.ClusterAddrs,
+			Username:        o.Username,
+			Password:        o.Password,
+			TLSConfig:       o.TLSConfig,
+			PoolSize:        o.PoolSize,
+			MinIdleConns:    o.MinIdleConns,
+			DialTimeout:     o.DialTimeout,
+			ReadTimeout:     o.ReadTimeout,
+			WriteTimeout:    o.WriteTimeout,
+			MaxRetries:      o.MaxRetries,
+			MinRetryBackoff: o.MinRetryBackoff,
+			MaxRetryBackoff: o.MaxRetryBackoff,
+		})
+	case len(o.SentinelAddrs) > 0:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:      o.MasterName,
+			SentinelAddrs:   o.SentinelAddrs,
+			Username:        o.Username,
+			Password:        o.Password,
+			DB:              o.DB,
+			TLSConfig:       o.TLSConfig,
+			PoolSize:        o.PoolSize,
+			MinIdleConns:    o.MinIdleConns,
+			DialTimeout:     o.DialTimeout,
+			ReadTimeout:     o.ReadTimeout,
+			WriteTimeout:    o.WriteTimeout,
+			MaxRetries:      o.MaxRetries,
+			MinRetryBackoff: o.MinRetryBackoff,
+			MaxRetryBackoff: o.MaxRetryBackoff,
+		})
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:            o.Addr,
+		Username:        o.Username,
+		Password:        o.Password,
+		DB:              o.DB,
+		TLSConfig:       o.TLSConfig,
+		PoolSize:        o.PoolSize,
+		MinIdleConns:    o.MinIdleConns,
+		DialTimeout:     o.DialTimeout,
+		ReadTimeout:     o.ReadTimeout,
+		WriteTimeout:    o.WriteTimeout,
+		MaxRetries:      o.MaxRetries,
+		MinRetryBackoff: o.MinRetryBackoff,
+		MaxRetryBackoff: o.MaxRetryBackoff,
+	})
+}
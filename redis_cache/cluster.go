@@ -0,0 +1,196 @@
+package redis_cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DefaultClusterShards is the number of shards a cache on a Redis Cluster
+// uses when WithShards is not given.
+const DefaultClusterShards = 16
+
+// shard is one slice of the cache's keyspace with its own LRU index, access
+// clock and versions hash. On a Redis Cluster every key of a shard carries
+// the shard's hash tag, so it lives in the same hash slot as the shard's
+// bookkeeping and the scripts can touch them together; different shards
+// spread over the cluster's nodes.
+type shard struct {
+	tag        string // "{n}" placed before each key, or empty for an untagged cache
+	lruKey     string
+	clockKey   string
+	versionKey string
+}
+
+// WithShards splits the cache into n shards, each evicting on its own with
+// an n-th of maxSize (rounded up), like in_memory.ShardedCache. It is needed
+// on a Redis Cluster, where it defaults to DefaultClusterShards and should
+// be well above the number of masters so keys spread evenly; elsewhere it
+// defaults to 1. A sharded cache stores each key under a hash tag, as
+// prefix + "{n}" + key, so the prefix must not contain braces.
+func WithShards(n int) Option {
+	return func(c *Cache) {
+		c.shardCount = n
+	}
+}
+
+// NewWithClient returns a cache using client, which may be a single node,
+// Sentinel failover or Cluster client. The cache closes client on Close.
+func NewWithClient(client redis.UniversalClient, maxSize int, opts ...Option) *Cache {
+	c := &Cache{
+		client:     client,
+		maxSize:    maxSize,
+		codec:      JSONCodec,
+		prefix:     DefaultPrefix,
+		defaultTTL: StandardTTL,
+	}
+	_, c.cluster = client.(*redis.ClusterClient)
+	if c.cluster {
+		c.shardCount = DefaultClusterShards
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.shardCount < 1 {
+		c.shardCount = 1
+	}
+	c.tagged = c.cluster || c.shardCount > 1
+	if c.tagged && c.maxSize > 0 {
+		c.maxSize = (c.maxSize + c.shardCount - 1) / c.shardCount
+	}
+
+	// The bookkeeping keys live outside the key namespace so they never
+	// show up in listings of the cache's keys.
+	c.shards = make([]shard, c.shardCount)
+	c.bookkeeping = make(map[string]bool, 3*c.shardCount)
+	for i := range c.shards {
+		base := "redis_cache:" + c.prefix
+		sh := &c.shards[i]
+		if c.tagged {
+			sh.tag = "{" + strconv.Itoa(i) + "}"
+			base += ":" + sh.tag
+		}
+		sh.lruKey = base + ":lru"
+		sh.clockKey = base + ":clock"
+		sh.versionKey = base + ":version"
+		c.bookkeeping[sh.lruKey] = true
+		c.bookkeeping[sh.clockKey] = true
+		c.bookkeeping[sh.versionKey] = true
+	}
+	return c
+}
+
+// NewFailoverCache returns a cache on the primary of a Sentinel-managed
+// Redis, following it through failovers.
+func NewFailoverCache(failover *redis.FailoverOptions, maxSize int, opts ...Option) *Cache {
+	return NewWithClient(redis.NewFailoverClient(failover), maxSize, opts...)
+}
+
+// NewClusterCache returns a cache spread over the masters of a Redis
+// Cluster. See WithShards.
+func NewClusterCache(cluster *redis.ClusterOptions, maxSize int, opts ...Option) *Cache {
+	return NewWithClient(redis.NewClusterClient(cluster), maxSize, opts...)
+}
+
+// shardFor returns the shard that owns the cache key k.
+func (c *Cache) shardFor(k string) *shard {
+	if len(c.shards) == 1 {
+		return &c.shards[0]
+	}
+	// FNV-1a
+	h := uint32(2166136261)
+	for i := 0; i < len(k); i++ {
+		h ^= uint32(k[i])
+		h *= 16777619
+	}
+	return &c.shards[h%uint32(len(c.shards))]
+}
+
+// byShard groups keys by shard, keeping their order within each shard.
+func (c *Cache) byShard(keys []string) map[*shard][]string {
+	groups := make(map[*shard][]string)
+	for _, key := range keys {
+		sh := c.shardFor(key)
+		groups[sh] = append(groups[sh], key)
+	}
+	return groups
+}
+
+// userKey returns the cache key stored under redisKey.
+func (c *Cache) userKey(redisKey string) string {
+	key := strings.TrimPrefix(redisKey, c.prefix)
+	if c.tagged {
+		if end := strings.IndexByte(key, '}'); strings.HasPrefix(key, "{") && end > 0 {
+			key = key[end+1:]
+		}
+	}
+	return key
+}
+
+// scan walks the cache's namespace with SCAN, on every master of a
+// cluster, calling fn with each batch of Redis keys found. Calls to fn are
+// serialised.
+func (c *Cache) scan(ctx context.Context, fn func(keys []string) error) error {
+	cluster, ok := c.client.(*redis.ClusterClient)
+	if !ok {
+		return c.scanNode(ctx, c.client, fn)
+	}
+	var lock sync.Mutex
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		return c.scanNode(ctx, master, func(keys []string) error {
+			lock.Lock()
+			defer lock.Unlock()
+			return fn(keys)
+		})
+	})
+}
+
+// scanNode is scan for a single Redis node.
+func (c *Cache) scanNode(ctx context.Context, node redis.Cmdable, fn func(keys []string) error) error {
+	match := escapePattern(c.prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, match, scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		// With an empty prefix the bookkeeping keys match too.
+		keys = c.withoutBookkeeping(keys)
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// unlink removes keys and returns how many existed. On a cluster, where one
+// command may not span hash slots, each key is unlinked on its own in a
+// pipeline.
+func (c *Cache) unlink(ctx context.Context, keys []string) (int64, error) {
+	if !c.cluster {
+		return c.client.Unlink(ctx, keys...).Result()
+	}
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Unlink(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, cmd := range cmds {
+		n += cmd.Val()
+	}
+	return n, nil
+}
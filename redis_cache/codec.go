@@ -0,0 +1,477 @@
+package redis_cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Codec converts values to and from the bytes stored in Redis.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal decodes data into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSONCodec stores values as JSON. It is the default codec, and stores
+	// integers as plain decimal strings so INCR-style commands still work.
+	JSONCodec Codec = jsonCodec{}
+	// GobCodec stores values with encoding/gob. Values are wrapped in an
+	// interface so they can be decoded without knowing their type, which means
+	// every type other than gob's built-in basic types must be registered with
+	// gob.Register.
+	GobCodec Codec = gobCodec{}
+	// BinaryCodec stores nil, booleans, numbers, strings, byte slices,
+	// fixed-size structs and slices/maps of those in a compact tagged binary
+	// format, similar to MessagePack. A struct is fixed-size if
+	// encoding/binary can encode it: every field is a bool, a number or an
+	// array or struct of those, and every named field is exported. Other
+	// structs are rejected with ErrUnsupportedType. A struct is stored without
+	// its type, so it can only be decoded into a struct of the same layout;
+	// decoding it into an interface{} yields its encoded bytes.
+	BinaryCodec Codec = binaryCodec{}
+	// RawCodec stores []byte and string values as-is.
+	RawCodec Codec = rawCodec{}
+)
+
+// ErrUnsupportedType is returned when a codec cannot encode or decode a type.
+var ErrUnsupportedType = errors.New("codec: unsupported type")
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// gobValue wraps every gob-encoded value so that it can be decoded into an
+// interface{} without knowing its type in advance.
+type gobValue struct {
+	V interface{}
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobValue{V: v}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	var wrapped gobValue
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&wrapped); err != nil {
+		return err
+	}
+	return assign(v, wrapped.V)
+}
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("%w: raw codec cannot encode %T", ErrUnsupportedType, v)
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *[]byte:
+		*v = append((*v)[:0], data...)
+		return nil
+	case *string:
+		*v = string(data)
+		return nil
+	case *interface{}:
+		*v = append([]byte(nil), data...)
+		return nil
+	}
+	return fmt.Errorf("%w: raw codec cannot decode into %T", ErrUnsupportedType, v)
+}
+
+// Type tags used by binaryCodec.
+const (
+	tagNil byte = iota
+	tagFalse
+	tagTrue
+	tagInt
+	tagUint
+	tagFloat
+	tagString
+	tagBytes
+	tagArray
+	tagMap
+	tagFixed // A fixed-size struct encoded by encoding/binary
+)
+
+// fixedValue is a decoded tagFixed value, waiting for assign to learn the
+// struct type it must be read into.
+type fixedValue []byte
+
+type binaryCodec struct{}
+
+func (binaryCodec) Marshal(v interface{}) ([]byte, error) {
+	return appendBinary(nil, reflect.ValueOf(v))
+}
+
+func (binaryCodec) Unmarshal(data []byte, v interface{}) error {
+	decoded, rest, err := readBinary(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("binary codec: trailing data")
+	}
+	return assign(v, decoded)
+}
+
+func appendBinary(buf []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(buf, tagNil), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return append(buf, tagNil), nil
+		}
+		return appendBinary(buf, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, tagTrue), nil
+		}
+		return append(buf, tagFalse), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(append(buf, tagInt), v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(append(buf, tagUint), v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(buf, tagFloat), math.Float64bits(v.Float())), nil
+	case reflect.String:
+		buf = binary.AppendUvarint(append(buf, tagString), uint64(v.Len()))
+		return append(buf, v.String()...), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf = binary.AppendUvarint(append(buf, tagBytes), uint64(v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				buf = append(buf, byte(v.Index(i).Uint()))
+			}
+			return buf, nil
+		}
+		buf = binary.AppendUvarint(append(buf, tagArray), uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			if buf, err = appendBinary(buf, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		buf = binary.AppendUvarint(append(buf, tagMap), uint64(v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			var err error
+			if buf, err = appendBinary(buf, iter.Key()); err != nil {
+				return nil, err
+			}
+			if buf, err = appendBinary(buf, iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Struct:
+		size := binary.Size(v.Interface())
+		if size < 0 || !exportedFields(v.Type()) {
+			return nil, fmt.Errorf("%w: binary codec cannot encode %s: struct is not fixed-size or has unexported fields", ErrUnsupportedType, v.Type())
+		}
+		fixed := bytes.NewBuffer(binary.AppendUvarint(append(buf, tagFixed), uint64(size)))
+		if err := binary.Write(fixed, binary.BigEndian, v.Interface()); err != nil {
+			return nil, err
+		}
+		return fixed.Bytes(), nil
+	}
+	return nil, fmt.Errorf("%w: binary codec cannot encode %s", ErrUnsupportedType, v.Type())
+}
+
+var errShortBuffer = errors.New("binary codec: unexpected end of data")
+
+func readBinary(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, errShortBuffer
+	}
+	tag, data := data[0], data[1:]
+
+	switch tag {
+	case tagNil:
+		return nil, data, nil
+	case tagFalse:
+		return false, data, nil
+	case tagTrue:
+		return true, data, nil
+	case tagInt:
+		n, size := binary.Varint(data)
+		if size <= 0 {
+			return nil, nil, errShortBuffer
+		}
+		return n, data[size:], nil
+	case tagUint:
+		n, size := binary.Uvarint(data)
+		if size <= 0 {
+			return nil, nil, errShortBuffer
+		}
+		return n, data[size:], nil
+	case tagFloat:
+		if len(data) < 8 {
+			return nil, nil, errShortBuffer
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	case tagString, tagBytes, tagFixed:
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) < n {
+			return nil, nil, errShortBuffer
+		}
+		data = data[size:]
+		switch tag {
+		case tagString:
+			return string(data[:n]), data[n:], nil
+		case tagFixed:
+			return fixedValue(append([]byte(nil), data[:n]...)), data[n:], nil
+		}
+		return append([]byte(nil), data[:n]...), data[n:], nil
+	case tagArray:
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) < n {
+			return nil, nil, errShortBuffer
+		}
+		data = data[size:]
+		items := make([]interface{}, n)
+		for i := range items {
+			var err error
+			if items[i], data, err = readBinary(data); err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, data, nil
+	case tagMap:
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) < n {
+			return nil, nil, errShortBuffer
+		}
+		data = data[size:]
+		items := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			key, rest, err := readBinary(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, nil, errors.New("binary codec: map key is not a string")
+			}
+			if items[keyString], data, err = readBinary(rest); err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, data, nil
+	}
+	return nil, nil, fmt.Errorf("binary codec: unknown type tag %d", tag)
+}
+
+// assign stores a decoded value into the value pointed to by dst, converting
+// between numeric kinds and building typed slices and maps where needed.
+func assign(dst interface{}, value interface{}) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("codec: decode target must be a non-nil pointer, got %T", dst)
+	}
+	return assignValue(ptr.Elem(), value)
+}
+
+func assignValue(dst reflect.Value, value interface{}) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if fixed, ok := value.(fixedValue); ok {
+		return assignFixed(dst, fixed)
+	}
+
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumber(src.Kind()) {
+			return assignNumber(dst, src)
+		}
+	case reflect.Array:
+		switch items := value.(type) {
+		case []interface{}:
+			if len(items) != dst.Len() {
+				break
+			}
+			for i, item := range items {
+				if err := assignValue(dst.Index(i), item); err != nil {
+					return err
+				}
+			}
+			return nil
+		case []byte:
+			if len(items) != dst.Len() || dst.Type().Elem().Kind() != reflect.Uint8 {
+				break
+			}
+			reflect.Copy(dst, reflect.ValueOf(items))
+			return nil
+		}
+	case reflect.Slice:
+		if items, ok := value.([]interface{}); ok {
+			slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+			for i, item := range items {
+				if err := assignValue(slice.Index(i), item); err != nil {
+					return err
+				}
+			}
+			dst.Set(slice)
+			return nil
+		}
+	case reflect.Map:
+		if items, ok := value.(map[string]interface{}); ok && dst.Type().Key().Kind() == reflect.String {
+			m := reflect.MakeMapWithSize(dst.Type(), len(items))
+			for key, item := range items {
+				elem := reflect.New(dst.Type().Elem()).Elem()
+				if err := assignValue(elem, item); err != nil {
+					return err
+				}
+				m.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+			}
+			dst.Set(m)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot decode %T into %s", ErrUnsupportedType, value, dst.Type())
+}
+
+// exportedFields reports whether every field of t, and of the structs it
+// contains, is exported or blank, so that encoding/binary can decode into it.
+func exportedFields(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return exportedFields(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Name != "_" && (!field.IsExported() || !exportedFields(field.Type)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// assignFixed reads a struct encoded by encoding/binary into dst, or stores
+// its bytes if dst is an interface.
+func assignFixed(dst reflect.Value, data fixedValue) error {
+	switch dst.Kind() {
+	case reflect.Interface:
+		return assignValue(dst, []byte(data))
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignFixed(elem.Elem(), data); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Struct:
+		if binary.Size(dst.Addr().Interface()) == len(data) {
+			return binary.Read(bytes.NewReader(data), binary.BigEndian, dst.Addr().Interface())
+		}
+	}
+	return fmt.Errorf("%w: cannot decode a %d-byte struct into %s", ErrUnsupportedType, len(data), dst.Type())
+}
+
+// assignNumber stores the number src in dst, failing rather than wrapping an
+// integer that is out of range or truncating a float that is not a whole
+// number.
+func assignNumber(dst, src reflect.Value) error {
+	var ok bool
+	switch {
+	case src.CanInt():
+		n := src.Int()
+		switch {
+		case dst.CanInt():
+			ok = !dst.OverflowInt(n)
+		case dst.CanUint():
+			ok = n >= 0 && !dst.OverflowUint(uint64(n))
+		default:
+			f := src.Convert(dst.Type()).Float()
+			ok = f >= -(1<<63) && f < 1<<63 && int64(f) == n
+		}
+	case src.CanUint():
+		n := src.Uint()
+		switch {
+		case dst.CanInt():
+			ok = n <= math.MaxInt64 && !dst.OverflowInt(int64(n))
+		case dst.CanUint():
+			ok = !dst.OverflowUint(n)
+		default:
+			f := src.Convert(dst.Type()).Float()
+			ok = f < 1<<64 && uint64(f) == n
+		}
+	default:
+		f := src.Float()
+		switch {
+		case dst.CanInt():
+			ok = f == math.Trunc(f) && f >= -(1<<63) && f < 1<<63 && !dst.OverflowInt(int64(f))
+		case dst.CanUint():
+			ok = f == math.Trunc(f) && f >= 0 && f < 1<<64 && !dst.OverflowUint(uint64(f))
+		default:
+			ok = !dst.OverflowFloat(f)
+		}
+	}
+	if !ok {
+		return fmt.Errorf("codec: %v does not fit in %s", src, dst.Type())
+	}
+	dst.Set(src.Convert(dst.Type()))
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
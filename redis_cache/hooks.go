@@ -0,0 +1,59 @@
+package redis_cache
+
+import (
+	"github.com/Devisree146/Go_project-library.git/cache"
+)
+
+// OnEvict registers fn to be called when the cache sees a key leave it:
+// ReasonCapacity when a Set evicts least recently used keys, ReasonDeleted
+// for Delete, ReasonCleared for DeleteAll, and ReasonExpired when a Get or
+// Set finds that Redis has expired a key the LRU index still listed. data is
+// the encoded value, or nil when Redis no longer holds it (expired and
+// cleared keys). Hooks run in the calling goroutine once the Redis call has
+// returned. Only this Cache's own calls are reported, not those made by
+// other clients sharing the namespace.
+func (c *Cache) OnEvict(fn func(key string, data []byte, reason cache.EvictionReason)) {
+	c.hooksLock.Lock()
+	defer c.hooksLock.Unlock()
+
+	c.hooks = append(c.hooks, fn)
+}
+
+// hasHooks reports whether any OnEvict hooks are registered, so callers can
+// skip fetching values nobody will see.
+func (c *Cache) hasHooks() bool {
+	c.hooksLock.RLock()
+	defer c.hooksLock.RUnlock()
+
+	return len(c.hooks) > 0
+}
+
+// notify calls the OnEvict hooks for a Redis key.
+func (c *Cache) notify(redisKey string, data []byte, reason cache.EvictionReason) {
+	c.hooksLock.RLock()
+	hooks := c.hooks
+	c.hooksLock.RUnlock()
+
+	key := c.userKey(redisKey)
+	for _, hook := range hooks {
+		hook(key, data, reason)
+	}
+}
+
+// notifyDropped counts and reports the (key, reason, value) triples returned
+// by setScript.
+func (c *Cache) notifyDropped(reply []interface{}) {
+	for i := 0; i+2 < len(reply); i += 3 {
+		key, _ := reply[i].(string)
+		reason := cache.ReasonCapacity
+		if reply[i+1] == "expired" {
+			reason = cache.ReasonExpired
+		}
+		var data []byte
+		if value, ok := reply[i+2].(string); ok {
+			data = []byte(value)
+		}
+		c.counters.evicted(reason, 1)
+		c.notify(key, data, reason)
+	}
+}
@@ -0,0 +1,40 @@
+package redis_cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WithNegativeTTL makes GetOrLoad remember, for d, keys whose loader
+// returned ErrCacheMiss. Negative entries are kept in this process only.
+// Zero, the default, calls the loader on every miss.
+func WithNegativeTTL(d time.Duration) Option {
+	return func(c *Cache) {
+		c.loads.NegativeTTL = d
+	}
+}
+
+// GetOrLoad returns the value stored under key decoded as a T. On a miss it
+// calls loader, once for all concurrent callers in this process with the
+// same key, and stores the result for the cache's default TTL. If loader returns an error
+// wrapping ErrCacheMiss, GetOrLoad returns ErrCacheMiss.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, loader func() (T, error)) (T, error) {
+	value, err := c.loads.GetOrLoad(ctx, key,
+		func() (interface{}, error) { return GetAsCtx[T](ctx, c, key) },
+		func() (interface{}, error) {
+			value, err := loader()
+			return value, err
+		},
+		func(value interface{}) error { return c.SetValueCtx(ctx, key, value, c.defaultTTL) },
+	)
+
+	// Concurrent callers share one load, so a caller asking for a different
+	// type than the one that ran the loader can receive a mismatched value.
+	typed, ok := value.(T)
+	if !ok && value != nil {
+		var zero T
+		return zero, fmt.Errorf("redis_cache: value loaded for %q is %T, not %T", key, value, zero)
+	}
+	return typed, err
+}
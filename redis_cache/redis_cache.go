@@ -3,128 +3,402 @@ package redis_cache
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Devisree146/Go_project-library.git/cache"
 	"github.com/go-redis/redis/v8"
 )
 
 // ErrCacheMiss indicates that a requested key was not found in the cache.
-var ErrCacheMiss = errors.New("cache: key not found")
+var ErrCacheMiss = cache.ErrCacheMiss
 
 type Cache struct {
-	client  *redis.Client
-	maxSize int
+	client     redis.UniversalClient
+	maxSize    int // Per shard
+	codec      Codec
+	prefix     string
+	timeout    time.Duration
+	defaultTTL time.Duration // Used by the cache.Cache methods when no TTL is given
+	cluster    bool
+	tagged     bool // Keys carry their shard's hash tag
+	shardCount int
+	shards     []shard
+
+	bookkeeping map[string]bool // Redis keys of the shards' LRU indexes, clocks and versions
+
+	hooksLock sync.RWMutex
+	hooks     []func(key string, data []byte, reason cache.EvictionReason)
+
+	counters counters
+	loads    cache.Group[string, interface{}] // Coalesces GetOrLoad misses
+}
+
+// Option configures optional Cache behaviour in NewRedisCache.
+type Option func(*Cache)
+
+// WithCodec sets the codec used to encode values. The default is JSONCodec.
+func WithCodec(codec Codec) Option {
+	return func(c *Cache) {
+		c.codec = codec
+	}
+}
+
+// WithPrefix sets the namespace prepended to every key the cache stores.
+// Caches with different prefixes can share one Redis database without
+// listing, evicting or deleting each other's keys. The default is DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// WithTimeout bounds every operation to d, on top of any deadline already on
+// the caller's context. Operations that run out of time return an error
+// wrapping cache.ErrTimeout. Zero, the default, adds no extra deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Cache) {
+		c.timeout = d
+	}
+}
+
+// WithDefaultTTL sets the TTL that Backend and GetOrLoad give entries when
+// the caller does not choose one. The default is StandardTTL.
+func WithDefaultTTL(d time.Duration) Option {
+	return func(c *Cache) {
+		c.defaultTTL = d
+	}
 }
 
 const (
 	StandardTTL = 5 * time.Minute // Exported standard TTL of 5 minutes
 )
 
-func NewRedisCache(address, password string, db, maxSize int) *Cache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     address,
-		Password: password,
-		DB:       db,
-	})
+// DefaultPrefix is the key namespace used when WithPrefix is not given.
+const DefaultPrefix = "cache:"
+
+// scanBatchSize is the number of keys requested per SCAN and removed per UNLINK.
+const scanBatchSize = 100
+
+// NewRedisCache connects to a single Redis server. Use NewWithOptions for
+// TLS, ACL users and connection pool settings.
+func NewRedisCache(address, password string, db, maxSize int, opts ...Option) *Cache {
+	return NewWithOptions(ClientOptions{Addr: address, Password: password, DB: db}, maxSize, opts...)
+}
+
+// key returns the Redis key that stores the cache key k.
+func (c *Cache) key(k string) string {
+	return c.prefix + c.shardFor(k).tag + k
+}
+
+// withTimeout applies the cache's per-operation timeout to ctx.
+func (c *Cache) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
 
-	return &Cache{
-		client:  client,
-		maxSize: maxSize,
+// ttlMillis converts ttl to the milliseconds the scripts expect, rounding up
+// so that a positive ttl under a millisecond does not become 0, which means
+// no expiry.
+func ttlMillis(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
 	}
+	return int64((ttl + time.Millisecond - 1) / time.Millisecond)
 }
 
 func (c *Cache) Set(key string, value int, ttl time.Duration) error {
-	ctx := context.Background()
-	err := c.client.Set(ctx, key, value, ttl).Err()
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+// SetCtx is like Set but honours the deadline and cancellation of ctx.
+func (c *Cache) SetCtx(ctx context.Context, key string, value int, ttl time.Duration) error {
+	return c.SetValueCtx(ctx, key, value, ttl)
+}
+
+func (c *Cache) Get(key string) (int, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+// GetCtx is like Get but honours the deadline and cancellation of ctx.
+func (c *Cache) GetCtx(ctx context.Context, key string) (int, error) {
+	return GetAsCtx[int](ctx, c, key)
+}
+
+// SetValue encodes value with the cache's codec and stores it under key.
+// If the cache grows beyond maxSize the least recently used keys are evicted.
+func (c *Cache) SetValue(key string, value interface{}, ttl time.Duration) error {
+	return c.SetValueCtx(context.Background(), key, value, ttl)
+}
+
+// SetValueCtx is like SetValue but honours the deadline and cancellation of ctx.
+func (c *Cache) SetValueCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	// Perform LRU eviction if cache exceeds maxSize
-	c.performLRUEviction()
+	_, _, err = c.set(ctx, key, data, ttl, "", 0)
+	return err
+}
 
-	return nil
+// set runs setScript for an encoded value with a condition ("", "NX", "XX"
+// or "CAS" with version) and returns the script's status and the key's new
+// version.
+func (c *Cache) set(ctx context.Context, key string, data []byte, ttl time.Duration, condition string, version uint64) (int64, uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	wantValues := 0
+	if c.hasHooks() {
+		wantValues = 1
+	}
+	reply, err := setScript.Run(ctx, c.client, c.scriptKeys([]string{key}),
+		data, ttlMillis(ttl), c.maxSize, wantValues, condition, version).Slice()
+	if err != nil {
+		return 0, 0, cache.WrapTimeout(err)
+	}
+
+	status, _ := reply[0].(int64)
+	newVersion, _ := reply[1].(int64)
+	if status == 1 {
+		c.counters.sets.Add(1)
+	}
+	dropped, _ := reply[2].([]interface{})
+	c.notifyDropped(dropped)
+	return status, uint64(newVersion), nil
 }
 
-func (c *Cache) Get(key string) (int, error) {
-	ctx := context.Background()
-	val, err := c.client.Get(ctx, key).Int()
+// GetValue decodes the value stored under key into the value pointed to by dst.
+func (c *Cache) GetValue(key string, dst interface{}) error {
+	return c.GetValueCtx(context.Background(), key, dst)
+}
+
+// GetValueCtx is like GetValue but honours the deadline and cancellation of ctx.
+func (c *Cache) GetValueCtx(ctx context.Context, key string, dst interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	reply, err := getScript.Run(ctx, c.client, c.scriptKeys([]string{key}), 0).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, ErrCacheMiss
+			c.counters.misses.Add(1)
+			return ErrCacheMiss
 		}
-		return 0, err
+		return cache.WrapTimeout(err)
+	}
+
+	data, ok := reply.(string)
+	if !ok {
+		// The script found the key in the LRU index but its value had expired.
+		c.counters.misses.Add(1)
+		c.counters.evicted(cache.ReasonExpired, 1)
+		c.notify(c.key(key), nil, cache.ReasonExpired)
+		return ErrCacheMiss
 	}
+	c.counters.hits.Add(1)
+	return c.codec.Unmarshal([]byte(data), dst)
+}
 
-	// Perform LRU eviction if cache exceeds maxSize
-	c.performLRUEviction()
+// GetAs returns the value stored under key decoded as a T.
+func GetAs[T any](c *Cache, key string) (T, error) {
+	return GetAsCtx[T](context.Background(), c, key)
+}
 
-	return val, nil
+// GetAsCtx is like GetAs but honours the deadline and cancellation of ctx.
+func GetAsCtx[T any](ctx context.Context, c *Cache, key string) (T, error) {
+	var value T
+	if err := c.GetValueCtx(ctx, key, &value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
 }
 
 func (c *Cache) Delete(key string) error {
-	ctx := context.Background()
-	err := c.client.Del(ctx, key).Err()
-	if err != nil {
-		return err
+	return c.DeleteCtx(context.Background(), key)
+}
+
+// DeleteCtx is like Delete but honours the deadline and cancellation of ctx.
+func (c *Cache) DeleteCtx(ctx context.Context, key string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	// The value is only fetched for the OnEvict hooks.
+	hooks := c.hasHooks()
+	var get *redis.StringCmd
+	var del *redis.IntCmd
+	sh := c.shardFor(key)
+	cmds, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if hooks {
+			get = pipe.Get(ctx, c.key(key))
+		}
+		del = pipe.Del(ctx, c.key(key))
+		pipe.ZRem(ctx, sh.lruKey, c.key(key))
+		pipe.HDel(ctx, sh.versionKey, c.key(key))
+		return nil
+	})
+	if err := pipelineErr(err, cmds); err != nil {
+		return cache.WrapTimeout(err)
 	}
 
-	// No need to perform LRU eviction on delete operation
+	c.counters.deletes.Add(1)
+	if del.Val() == 1 {
+		c.counters.evicted(cache.ReasonDeleted, 1)
+		if hooks {
+			c.notify(c.key(key), []byte(get.Val()), cache.ReasonDeleted)
+		}
+	}
 	return nil
 }
 
+// pipelineErr returns the error of a pipeline that fetched values with GET
+// for the hooks. go-redis reports only the first failed command, and a GET
+// of a missing key fails with redis.Nil, which is not an error here, so the
+// commands are checked one by one.
+func pipelineErr(err error, cmds []redis.Cmder) error {
+	if err == nil || !errors.Is(err, redis.Nil) {
+		return err
+	}
+	for _, cmd := range cmds {
+		cmdErr := cmd.Err()
+		if cmdErr == nil || (cmd.Name() == "get" && cmdErr == redis.Nil) {
+			continue
+		}
+		return cmdErr
+	}
+	return nil
+}
+
+// DeleteAll removes every key in the cache's namespace, leaving the rest of
+// the database untouched.
 func (c *Cache) DeleteAll() error {
-	ctx := context.Background()
-	err := c.client.FlushDB(ctx).Err()
+	return c.DeleteAllCtx(context.Background())
+}
+
+// DeleteAllCtx is like DeleteAll but honours the deadline and cancellation of ctx.
+func (c *Cache) DeleteAllCtx(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	hooks := c.hasHooks()
+	var cleared []string
+	err := c.scan(ctx, func(keys []string) error {
+		n, err := c.unlink(ctx, keys)
+		if err != nil {
+			return err
+		}
+		c.counters.evicted(cache.ReasonCleared, uint64(n))
+		if hooks {
+			cleared = append(cleared, keys...)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return cache.WrapTimeout(err)
+	}
+	// The clocks are kept so versions of new writes never repeat old ones.
+	bookkeeping := make([]string, 0, 2*len(c.shards))
+	for _, sh := range c.shards {
+		bookkeeping = append(bookkeeping, sh.lruKey, sh.versionKey)
+	}
+	if _, err := c.unlink(ctx, bookkeeping); err != nil {
+		return cache.WrapTimeout(err)
 	}
 
-	// No need to perform LRU eviction on delete all operation
+	for _, key := range cleared {
+		c.notify(key, nil, cache.ReasonCleared)
+	}
 	return nil
 }
 
-func (c *Cache) GetAllKeys() ([]string, error) {
-	ctx := context.Background()
-	keys, err := c.client.Keys(ctx, "*").Result()
+func (c *Cache) Exists(key string) (bool, error) {
+	return c.ExistsCtx(context.Background(), key)
+}
+
+// ExistsCtx is like Exists but honours the deadline and cancellation of ctx.
+func (c *Cache) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	n, err := c.client.Exists(ctx, c.key(key)).Result()
 	if err != nil {
-		return nil, err
+		return false, cache.WrapTimeout(err)
 	}
 
-	// Perform LRU eviction if cache exceeds maxSize
-	c.performLRUEviction()
+	return n > 0, nil
+}
 
-	return keys, nil
+// TTL returns the time left before key expires, or -1 if it has no expiry.
+func (c *Cache) TTL(key string) (time.Duration, error) {
+	return c.TTLCtx(context.Background(), key)
 }
 
-func (c *Cache) performLRUEviction() {
-	ctx := context.Background()
-	keys, err := c.client.Keys(ctx, "*").Result()
+// TTLCtx is like TTL but honours the deadline and cancellation of ctx.
+func (c *Cache) TTLCtx(ctx context.Context, key string) (time.Duration, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	ttl, err := c.client.PTTL(ctx, c.key(key)).Result()
 	if err != nil {
-		return
-	}
-
-	// If cache size exceeds maxSize, perform LRU eviction
-	if len(keys) > c.maxSize {
-		var oldestKey string
-		var oldestTime time.Time
-
-		// Find the oldest accessed key
-		for _, key := range keys {
-			ts, err := c.client.ObjectIdleTime(ctx, key).Result()
-			if err != nil {
-				continue
-			}
-			lastAccessed := time.Now().Add(-time.Second * time.Duration(ts.Seconds()))
-			if oldestTime.IsZero() || lastAccessed.Before(oldestTime) {
-				oldestTime = lastAccessed
-				oldestKey = key
-			}
+		return 0, cache.WrapTimeout(err)
+	}
+
+	// PTTL reports -2 for a missing key and -1 for a key without expiry.
+	switch {
+	case ttl == -2:
+		return 0, ErrCacheMiss
+	case ttl < 0:
+		return -1, nil
+	}
+	return ttl, nil
+}
+
+// GetAllKeys returns the keys in the cache's namespace, without the prefix.
+func (c *Cache) GetAllKeys() ([]string, error) {
+	return c.GetAllKeysCtx(context.Background())
+}
+
+// GetAllKeysCtx is like GetAllKeys but honours the deadline and cancellation of ctx.
+func (c *Cache) GetAllKeysCtx(ctx context.Context) ([]string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	var keys []string
+	err := c.scan(ctx, func(batch []string) error {
+		for _, key := range batch {
+			keys = append(keys, c.userKey(key))
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, cache.WrapTimeout(err)
+	}
+
+	return keys, nil
+}
+
+// Close closes the connection pool to Redis. The cache must not be used afterwards.
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
+// withoutBookkeeping filters the LRU indexes, clocks and versions out of keys.
+func (c *Cache) withoutBookkeeping(keys []string) []string {
+	filtered := keys[:0]
+	for _, key := range keys {
+		if !c.bookkeeping[key] {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
 
-		// Delete the oldest key
-		if oldestKey != "" {
-			c.client.Del(ctx, oldestKey)
+// escapePattern escapes the glob characters understood by SCAN MATCH.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
 		}
+		b.WriteRune(r)
 	}
+	return b.String()
 }
@@ -0,0 +1,196 @@
+package redis_cache
+
+import "github.com/go-redis/redis/v8"
+
+// The cache keeps its own LRU index instead of asking Redis for idle times:
+// every Get and Set stamps the key in a sorted set with a value from an
+// ever-increasing access clock, so the least recently used key is always the
+// first member of the set. Writes also record the clock value in a hash as
+// the key's version for CompareAndSwap; the clock survives DeleteAll, so a
+// version is never reused. Every script runs atomically in a single round
+// trip.
+
+// evictLua defines evict(lru, versions, maxSize, wantValues), which evicts
+// the least recently used keys while the index holds more than maxSize keys.
+// Redis expires keys without telling the index, so before evicting it checks
+// the oldest members, up to evictSweep beyond the excess, and drops the ones
+// that are gone; only the remaining excess is evicted, so dead members do not
+// take the place of live keys. It returns a flat list of (key, reason, value)
+// triples for the keys dropped from the index: reason is "capacity" for
+// evicted keys and "expired" for keys Redis had already expired. value is
+// only filled in for evicted keys when wantValues is '1'.
+const evictLua = `
+local evictSweep = 32
+
+local function evict(lru, versions, maxSize, wantValues)
+	local evicted = {}
+	if maxSize <= 0 then
+		return evicted
+	end
+	local excess = redis.call('ZCARD', lru) - maxSize
+	if excess <= 0 then
+		return evicted
+	end
+	local oldest = redis.call('ZRANGE', lru, 0, excess + evictSweep - 1)
+	local live = {}
+	for _, key in ipairs(oldest) do
+		if redis.call('EXISTS', key) == 0 then
+			redis.call('ZREM', lru, key)
+			redis.call('HDEL', versions, key)
+			table.insert(evicted, key)
+			table.insert(evicted, 'expired')
+			table.insert(evicted, false)
+			excess = excess - 1
+		else
+			table.insert(live, key)
+		end
+	end
+	for i = 1, math.min(excess, #live) do
+		local key = live[i]
+		redis.call('ZREM', lru, key)
+		redis.call('HDEL', versions, key)
+		local value = false
+		if wantValues == '1' then
+			value = redis.call('GET', key)
+		end
+		redis.call('DEL', key)
+		table.insert(evicted, key)
+		table.insert(evicted, 'capacity')
+		table.insert(evicted, value)
+	end
+	return evicted
+end
+`
+
+// stampLua defines stamp(key, lru, clock, versions), which records a write
+// of key in the LRU index and returns its new version.
+const stampLua = `
+local function stamp(key, lru, clock, versions)
+	local now = redis.call('INCR', clock)
+	redis.call('ZADD', lru, now, key)
+	redis.call('HSET', versions, key, now)
+	return now
+end
+`
+
+// setScript stores a value if the condition in ARGV[5] holds, records the
+// write and evicts as evict does. It returns {status, version, evicted}:
+// status is 1 if the value was stored, 0 if the key was present for 'NX' or
+// absent for 'XX' and 'CAS', and -1 if the version differed for 'CAS'.
+// version is the key's new version and evicted the triples from evict.
+//
+// KEYS[1] data key, KEYS[2] LRU index, KEYS[3] access clock, KEYS[4] versions
+// ARGV[1] value, ARGV[2] TTL in milliseconds (0 for none), ARGV[3] max size (0 for unbounded),
+// ARGV[4] 1 to return evicted values, ARGV[5] condition: empty for none, 'NX', 'XX' or 'CAS',
+// ARGV[6] expected version for 'CAS', where 0 matches a key without one
+var setScript = redis.NewScript(evictLua + stampLua + `
+local condition = ARGV[5]
+if condition ~= '' then
+	local exists = redis.call('EXISTS', KEYS[1]) == 1
+	if condition == 'NX' and exists then
+		return {0, 0, {}}
+	end
+	if (condition == 'XX' or condition == 'CAS') and not exists then
+		return {0, 0, {}}
+	end
+	if condition == 'CAS' and (redis.call('HGET', KEYS[4], KEYS[1]) or '0') ~= ARGV[6] then
+		return {-1, 0, {}}
+	end
+end
+
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+local version = stamp(KEYS[1], KEYS[2], KEYS[3], KEYS[4])
+return {1, version, evict(KEYS[2], KEYS[4], tonumber(ARGV[3]), ARGV[4])}
+`)
+
+// msetScript stores many values unconditionally: every value is stored and
+// stamped in order, and the index is trimmed once at the end. It returns the
+// triples from evict.
+//
+// KEYS[1..n] data keys, KEYS[n+1] LRU index, KEYS[n+2] access clock, KEYS[n+3] versions
+// ARGV[1] TTL in milliseconds (0 for none), ARGV[2] max size (0 for unbounded),
+// ARGV[3] 1 to return evicted values, ARGV[4..n+3] values
+var msetScript = redis.NewScript(evictLua + stampLua + `
+local n = #KEYS - 3
+local lru, clock, versions = KEYS[n + 1], KEYS[n + 2], KEYS[n + 3]
+local ttl = tonumber(ARGV[1])
+for i = 1, n do
+	if ttl > 0 then
+		redis.call('SET', KEYS[i], ARGV[i + 3], 'PX', ttl)
+	else
+		redis.call('SET', KEYS[i], ARGV[i + 3])
+	end
+	stamp(KEYS[i], lru, clock, versions)
+end
+return evict(lru, versions, tonumber(ARGV[2]), ARGV[3])
+`)
+
+// incrScript adds ARGV[1] to the integer stored under a key, creating it
+// from zero with the TTL in ARGV[2] if it is absent, and records the write.
+// It returns {result, evicted}. A value that is not an integer fails the
+// script with Redis's "not an integer" error.
+//
+// KEYS[1] data key, KEYS[2] LRU index, KEYS[3] access clock, KEYS[4] versions
+// ARGV[1] delta, ARGV[2] TTL in milliseconds for new keys (0 for none),
+// ARGV[3] max size (0 for unbounded), ARGV[4] 1 to return evicted values
+var incrScript = redis.NewScript(evictLua + stampLua + `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	local ttl = tonumber(ARGV[2])
+	if ttl > 0 then
+		redis.call('SET', KEYS[1], '0', 'PX', ttl)
+	else
+		redis.call('SET', KEYS[1], '0')
+	end
+end
+local result = redis.call('INCRBY', KEYS[1], ARGV[1])
+stamp(KEYS[1], KEYS[2], KEYS[3], KEYS[4])
+return {result, evict(KEYS[2], KEYS[4], tonumber(ARGV[3]), ARGV[4])}
+`)
+
+// getScript returns a value and records the access. Keys that have expired
+// are dropped from the LRU index, and reported by returning 1 instead of nil.
+// With ARGV[1] set to 1 it returns {value, version} for a present key.
+//
+// KEYS[1] data key, KEYS[2] LRU index, KEYS[3] access clock, KEYS[4] versions
+// ARGV[1] 1 to return the version
+var getScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+	-- Only keys still in the index had a value that expired.
+	if redis.call('ZREM', KEYS[2], KEYS[1]) == 1 then
+		redis.call('HDEL', KEYS[4], KEYS[1])
+		return 1
+	end
+	return false
+end
+redis.call('ZADD', KEYS[2], redis.call('INCR', KEYS[3]), KEYS[1])
+if ARGV[1] == '1' then
+	return {value, tonumber(redis.call('HGET', KEYS[4], KEYS[1]) or '0')}
+end
+return value
+`)
+
+// mgetScript is getScript for many keys, reading them with one MGET. Each
+// element of the reply is the value, nil for a missing key or 1 for a key
+// whose value expired.
+//
+// KEYS[1..n] data keys, KEYS[n+1] LRU index, KEYS[n+2] access clock, KEYS[n+3] versions
+var mgetScript = redis.NewScript(`
+local n = #KEYS - 3
+local lru, clock, versions = KEYS[n + 1], KEYS[n + 2], KEYS[n + 3]
+local values = redis.call('MGET', unpack(KEYS, 1, n))
+for i = 1, n do
+	if values[i] then
+		redis.call('ZADD', lru, redis.call('INCR', clock), KEYS[i])
+	elseif redis.call('ZREM', lru, KEYS[i]) == 1 then
+		redis.call('HDEL', versions, KEYS[i])
+		values[i] = 1
+	end
+end
+return values
+`)
@@ -0,0 +1,70 @@
+package redis_cache
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// counters tracks the operations made through one Cache. Other clients of
+// the same Redis database are not counted.
+type counters struct {
+	hits, misses, sets, deletes         atomic.Uint64
+	capacity, expired, deleted, cleared atomic.Uint64
+}
+
+// evicted counts n keys that left the cache for reason.
+func (c *counters) evicted(reason cache.EvictionReason, n uint64) {
+	switch reason {
+	case cache.ReasonCapacity:
+		c.capacity.Add(n)
+	case cache.ReasonExpired:
+		c.expired.Add(n)
+	case cache.ReasonDeleted:
+		c.deleted.Add(n)
+	case cache.ReasonCleared:
+		c.cleared.Add(n)
+	}
+}
+
+// Stats returns the counters of this Cache and the number of keys in its
+// LRU indexes, which may include keys that expired since they were last used.
+func (c *Cache) Stats() (cache.Stats, error) {
+	return c.StatsCtx(context.Background())
+}
+
+// StatsCtx is like Stats but honours the deadline and cancellation of ctx.
+func (c *Cache) StatsCtx(ctx context.Context) (cache.Stats, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cards := make([]*redis.IntCmd, len(c.shards))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, sh := range c.shards {
+			cards[i] = pipe.ZCard(ctx, sh.lruKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return cache.Stats{}, cache.WrapTimeout(err)
+	}
+	var entries int64
+	for _, card := range cards {
+		entries += card.Val()
+	}
+
+	return cache.Stats{
+		Hits:    c.counters.hits.Load(),
+		Misses:  c.counters.misses.Load(),
+		Sets:    c.counters.sets.Load(),
+		Deletes: c.counters.deletes.Load(),
+		Evictions: cache.EvictionStats{
+			Capacity: c.counters.capacity.Load(),
+			Expired:  c.counters.expired.Load(),
+			Deleted:  c.counters.deleted.Load(),
+			Cleared:  c.counters.cleared.Load(),
+		},
+		Entries: int(entries),
+	}, nil
+}
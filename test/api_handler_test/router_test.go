@@ -0,0 +1,438 @@
+package api_handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/api_handler"
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/config"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/gin-gonic/gin"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+	return api_handler.SetupRouter(in_memory.NewBackend(cache))
+}
+
+// Helper function to perform HTTP requests
+func performRequest(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouterSetGetDelete(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache", `{"key": "key1", "value": 100}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+	}
+
+	w = performRequest(router, "GET", "/cache?key=key1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"value":100`) {
+		t.Errorf("Expected value 100 in response, got %s", body)
+	}
+
+	w = performRequest(router, "DELETE", "/cache?key=key1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+
+	// Negative Test Case: the key is gone after deletion
+	w = performRequest(router, "GET", "/cache?key=key1", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// requireRedis skips the test unless the Redis server in cfg accepts connections.
+func requireRedis(t *testing.T, cfg config.Config) {
+	conn, err := net.DialTimeout("tcp", cfg.Redis.Addr, time.Second)
+	if err != nil {
+		t.Skipf("Redis is not reachable at %s: %v", cfg.Redis.Addr, err)
+	}
+	conn.Close()
+}
+
+func TestRedisRouterUsesConfiguredTTL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.TTL = config.Duration(time.Minute)
+	requireRedis(t, cfg)
+	router, backend, err := api_handler.SetupRedisCacheRouter(cfg)
+	if err != nil {
+		t.Fatalf("SetupRedisCacheRouter() error = %v", err)
+	}
+	defer backend.Close()
+	ctx := context.Background()
+	defer backend.Delete(ctx, "configured_ttl")
+
+	w := performRequest(router, "POST", "/cache", `{"key": "configured_ttl", "value": 1}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+	}
+	ttl, err := backend.(cache.TTLer).TTL(ctx, "configured_ttl")
+	if err != nil {
+		t.Fatalf("TTL() error = %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL() got = %v, want within (0, 1m]", ttl)
+	}
+}
+
+func TestRedisRoutersUseSeparateNamespaces(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	requireRedis(t, cfg)
+	redisRouter, redisBackend, err := api_handler.SetupRedisCacheRouter(cfg)
+	if err != nil {
+		t.Fatalf("SetupRedisCacheRouter() error = %v", err)
+	}
+	defer redisBackend.Close()
+	multiRouter, multiBackend, err := api_handler.SetupMultiCacheRouter(cfg)
+	if err != nil {
+		t.Fatalf("SetupMultiCacheRouter() error = %v", err)
+	}
+	defer multiBackend.Close()
+	defer performRequest(multiRouter, "DELETE", "/cache/all", "")
+
+	performRequest(multiRouter, "POST", "/cache", `{"key": "shared", "value": 1}`)
+	if w := performRequest(redisRouter, "DELETE", "/cache/all", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	l2 := redis_cache.NewRedisCache(cfg.Redis.Addr, "", 0, 0, redis_cache.WithPrefix(cfg.Redis.Prefix+"multi:"))
+	defer l2.Close()
+	if exists, err := l2.Exists("shared"); err != nil || !exists {
+		t.Errorf("Expected the multicache's Redis key to survive the Redis server's delete-all, got %v, %v", exists, err)
+	}
+}
+
+func TestRouterBadRequests(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache", `not json`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1, "ttl": "soon"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = performRequest(router, "GET", "/cache", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRouterPerRequestTTL(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1, "ttl": "50ms"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	w = performRequest(router, "GET", "/cache?key=key1", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRouterAllKeys(t *testing.T) {
+	router := newRouter()
+
+	performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1}`)
+	performRequest(router, "POST", "/cache", `{"key": "key2", "value": 2}`)
+
+	w := performRequest(router, "GET", "/cache/all", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "key1") || !strings.Contains(body, "key2") {
+		t.Errorf("Expected both keys in response, got %s", body)
+	}
+
+	w = performRequest(router, "DELETE", "/cache/all", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+
+	w = performRequest(router, "GET", "/cache/all", "")
+	if body := w.Body.String(); body != `{"keys":[]}` {
+		t.Errorf("Expected no keys after delete all, got %s", body)
+	}
+}
+
+func TestRouterArbitraryJSONValues(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache", `{"key": "user", "value": {"name": "alice", "roles": ["admin"]}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+	}
+
+	w = performRequest(router, "GET", "/cache?key=user", "")
+	if body := w.Body.String(); !strings.Contains(body, `"value":{"name":"alice","roles":["admin"]}`) {
+		t.Errorf("Expected object value in response, got %s", body)
+	}
+}
+
+func TestRouterStats(t *testing.T) {
+	router := newRouter()
+
+	performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1}`)
+	performRequest(router, "GET", "/cache?key=key1", "")
+	performRequest(router, "GET", "/cache?key=missing", "")
+
+	w := performRequest(router, "GET", "/cache/stats", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{`"hits":1`, `"misses":1`, `"sets":1`, `"entries":1`, `"hit_ratio":0.5`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s in response, got %s", want, body)
+		}
+	}
+
+	// Negative Test Case: a backend without stats
+	w = performRequest(api_handler.SetupRouter(slowBackend{}), "GET", "/cache/stats", "")
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotImplemented, w.Code)
+	}
+}
+
+// slowBackend is a cache.Cache whose every operation times out.
+type slowBackend struct{}
+
+func (slowBackend) Get(ctx context.Context, key string) (interface{}, error) {
+	return nil, cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) Delete(ctx context.Context, key string) error {
+	return cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) DeleteAll(ctx context.Context) error {
+	return cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) Keys(ctx context.Context) ([]string, error) {
+	return nil, cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) Exists(ctx context.Context, key string) (bool, error) {
+	return false, cache.WrapTimeout(context.DeadlineExceeded)
+}
+func (slowBackend) Close() error { return nil }
+
+func TestRouterTimeoutIsGatewayTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := api_handler.SetupRouter(slowBackend{})
+
+	w := performRequest(router, "GET", "/cache?key=key1", "")
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status code %d but got %d", http.StatusGatewayTimeout, w.Code)
+	}
+
+	w = performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1}`)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status code %d but got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
+
+func TestRouterMetrics(t *testing.T) {
+	router := newRouter()
+
+	performRequest(router, "POST", "/cache", `{"key": "key1", "value": 1}`)
+	performRequest(router, "GET", "/cache?key=key1", "")
+	performRequest(router, "GET", "/cache?key=missing", "")
+
+	w := performRequest(router, "GET", "/metrics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus text content type, got %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE cache_operation_duration_seconds histogram",
+		`cache_operation_duration_seconds_count{backend="cache",operation="get"} 2`,
+		`cache_operation_duration_seconds_bucket{backend="cache",operation="set",le="+Inf"} 1`,
+		`cache_http_requests_total{backend="cache",method="GET",route="/cache",code="404"} 1`,
+		`cache_http_requests_in_flight{backend="cache"} 1`, // The scrape itself
+		`cache_hit_ratio{backend="cache"} 0.5`,
+		`cache_evictions_total{backend="cache",reason="capacity"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s in response, got\n%s", want, body)
+		}
+	}
+
+	// Misses are not errors
+	if strings.Contains(body, `cache_operation_errors_total{backend="cache",operation="get"}`) {
+		t.Errorf("Expected no get errors, got\n%s", body)
+	}
+}
+
+func TestRouterMetricsCountsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := api_handler.SetupNamedRouter("slow", slowBackend{})
+
+	performRequest(router, "GET", "/cache?key=key1", "")
+	performRequest(router, "GET", "/nowhere", "")
+
+	body := performRequest(router, "GET", "/metrics", "").Body.String()
+	for _, want := range []string{
+		`cache_operation_errors_total{backend="slow",operation="get"} 1`,
+		`cache_http_requests_total{backend="slow",method="GET",route="unmatched",code="404"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s in response, got\n%s", want, body)
+		}
+	}
+	// A backend without stats exports no stats series
+	if strings.Contains(body, "cache_hit_ratio") {
+		t.Errorf("Expected no hit ratio, got\n%s", body)
+	}
+}
+
+func TestRouterBatch(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache/batch",
+		`{"entries": [{"key": "key1", "value": 1}, {"key": "key2", "value": "two", "ttl": "1m"}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); body != `{"results":[{"key":"key1","stored":true},{"key":"key2","stored":true}]}` {
+		t.Errorf("Unexpected set results %s", body)
+	}
+
+	w = performRequest(router, "POST", "/cache/batch/get", `{"keys": ["key1", "missing", "key2"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	want := `{"results":[{"key":"key1","found":true,"value":1},{"key":"missing","found":false},{"key":"key2","found":true,"value":"two"}]}`
+	if body := w.Body.String(); body != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+
+	w = performRequest(router, "POST", "/cache/batch/delete", `{"keys": ["key1", "missing"]}`)
+	if body := w.Body.String(); body != `{"results":[{"key":"key1","deleted":true},{"key":"missing","deleted":false}]}` {
+		t.Errorf("Unexpected delete results %s", body)
+	}
+
+	// Negative Test Cases: empty batches and bad TTLs
+	for path, body := range map[string]string{
+		"/cache/batch/get":    `{"keys": []}`,
+		"/cache/batch/delete": `not json`,
+		"/cache/batch":        `{"entries": [{"key": "key1", "value": 1, "ttl": "soon"}]}`,
+	} {
+		if w := performRequest(router, "POST", path, body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s but got %d", http.StatusBadRequest, path, w.Code)
+		}
+	}
+}
+
+func TestRouterBatchFallsBackPerKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// slowBackend has no batch support, so each key is tried on its own
+	w := performRequest(api_handler.SetupRouter(slowBackend{}), "POST", "/cache/batch/get", `{"keys": ["key1"]}`)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status code %d but got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
+
+func TestRouterCounters(t *testing.T) {
+	router := newRouter()
+
+	w := performRequest(router, "POST", "/cache/incr", `{"key": "counter", "delta": 5}`)
+	if w.Code != http.StatusOK || w.Body.String() != `{"key":"counter","value":5}` {
+		t.Fatalf("Unexpected incr response %d %s", w.Code, w.Body.String())
+	}
+	w = performRequest(router, "POST", "/cache/decr", `{"key": "counter"}`)
+	if w.Body.String() != `{"key":"counter","value":4}` {
+		t.Errorf("Unexpected decr response %s", w.Body.String())
+	}
+
+	// Negative Test Cases: a missing key and a value that is not an integer
+	if w := performRequest(router, "POST", "/cache/incr", `{"delta": 1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+	}
+	performRequest(router, "POST", "/cache", `{"key": "text", "value": "hello"}`)
+	if w := performRequest(router, "POST", "/cache/incr", `{"key": "text"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d but got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestRouterConditionalWrites(t *testing.T) {
+	router := newRouter()
+
+	if w := performRequest(router, "PUT", "/cache", `{"key": "key1", "value": 1}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+	if w := performRequest(router, "POST", "/cache/add", `{"key": "key1", "value": 1}`); w.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+	}
+	if w := performRequest(router, "POST", "/cache/add", `{"key": "key1", "value": 2}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d but got %d", http.StatusConflict, w.Code)
+	}
+	if w := performRequest(router, "PUT", "/cache", `{"key": "key1", "value": 3}`); w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+
+	w := performRequest(router, "GET", "/cache/versioned?key=key1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	var got struct {
+		Value   int    `json:"value"`
+		Version uint64 `json:"version"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.Value != 3 {
+		t.Fatalf("Unexpected versioned response %s", w.Body.String())
+	}
+
+	cas := fmt.Sprintf(`{"key": "key1", "value": 4, "version": %d}`, got.Version)
+	if w := performRequest(router, "POST", "/cache/cas", cas); w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	// Negative Test Case: the same version again no longer matches
+	if w := performRequest(router, "POST", "/cache/cas", cas); w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d but got %d", http.StatusConflict, w.Code)
+	}
+	if w := performRequest(router, "GET", "/cache/versioned?key=missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRouterAtomicUnsupported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := api_handler.SetupRouter(slowBackend{})
+
+	// Negative Test Case: a backend without counters
+	if w := performRequest(router, "POST", "/cache/incr", `{"key": "counter"}`); w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotImplemented, w.Code)
+	}
+}
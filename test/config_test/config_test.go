@@ -0,0 +1,280 @@
+package config_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/config"
+)
+
+func writeFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(cfg, config.Default()) {
+		t.Errorf("Load() got = %+v, want defaults %+v", cfg, config.Default())
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeFile(t, `
+redis:
+  addr: redis.internal:6379
+  db: 2
+size: 100
+ttl: 90s
+ports:
+  multicache: 9090
+`)
+
+	cfg, err := config.Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.Redis.Addr != "redis.internal:6379" || cfg.Redis.DB != 2 {
+		t.Errorf("Load() redis got = %+v", cfg.Redis)
+	}
+	if cfg.Size != 100 || time.Duration(cfg.TTL) != 90*time.Second {
+		t.Errorf("Load() got size %d, ttl %s, want 100, 1m30s", cfg.Size, cfg.TTL)
+	}
+	// Settings missing from the file keep their defaults.
+	if cfg.Ports.MultiCache != 9090 || cfg.Ports.InMemory != 8081 {
+		t.Errorf("Load() ports got = %+v", cfg.Ports)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "size: 10\nttl: 30\n")
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("SIZE", "20")
+	t.Setenv("REDIS_ADDR", "env:6379")
+	t.Setenv("MAX_BYTES", "1048576")
+
+	cfg, err := config.Load([]string{"-size", "30"})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.Size != 30 {
+		t.Errorf("Load() size got = %d, want the flag value 30", cfg.Size)
+	}
+	if cfg.Redis.Addr != "env:6379" {
+		t.Errorf("Load() redis addr got = %s, want the env value", cfg.Redis.Addr)
+	}
+	if cfg.MaxBytes != 1<<20 {
+		t.Errorf("Load() max bytes got = %d, want the env value 1048576", cfg.MaxBytes)
+	}
+	// A plain number is read as seconds.
+	if time.Duration(cfg.TTL) != 30*time.Second {
+		t.Errorf("Load() ttl got = %s, want the file value 30s", cfg.TTL)
+	}
+}
+
+func TestLoadSnapshotSettings(t *testing.T) {
+	t.Setenv("SNAPSHOT_PATH", "/var/lib/cache/in_memory.snapshot")
+	t.Setenv("LOG_SYNC", "always")
+
+	cfg, err := config.Load([]string{"-snapshot-interval", "5m", "-log-path", "/var/lib/cache/in_memory.log"})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.SnapshotPath != "/var/lib/cache/in_memory.snapshot" || time.Duration(cfg.SnapshotInterval) != 5*time.Minute {
+		t.Errorf("Load() got snapshot path %q, interval %s", cfg.SnapshotPath, cfg.SnapshotInterval)
+	}
+	if cfg.LogPath != "/var/lib/cache/in_memory.log" || cfg.LogSync != "always" {
+		t.Errorf("Load() got log path %q, sync %q", cfg.LogPath, cfg.LogSync)
+	}
+}
+
+func TestLoadRedisTopology(t *testing.T) {
+	path := writeFile(t, `
+redis:
+  master_name: mymaster
+  sentinel_addrs: [sentinel-1:26379, sentinel-2:26379]
+`)
+	cfg, err := config.Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	want := []string{"sentinel-1:26379", "sentinel-2:26379"}
+	if cfg.Redis.MasterName != "mymaster" || !reflect.DeepEqual(cfg.Redis.SentinelAddrs, want) {
+		t.Errorf("Load() got master %q, sentinels %v", cfg.Redis.MasterName, cfg.Redis.SentinelAddrs)
+	}
+
+	t.Setenv("REDIS_CLUSTER_ADDRS", "node-1:7000, node-2:7000,")
+	cfg, err = config.Load(nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if want := []string{"node-1:7000", "node-2:7000"}; !reflect.DeepEqual(cfg.Redis.ClusterAddrs, want) {
+		t.Errorf("Load() got cluster %v, want %v", cfg.Redis.ClusterAddrs, want)
+	}
+}
+
+func TestLoadRedisPrefix(t *testing.T) {
+	path := writeFile(t, "redis:\n  prefix: \"app:\"\n")
+	cfg, err := config.Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.Redis.Prefix != "app:" {
+		t.Errorf("Load() got prefix %q, want %q", cfg.Redis.Prefix, "app:")
+	}
+
+	t.Setenv("REDIS_PREFIX", "env:")
+	cfg, err = config.Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.Redis.Prefix != "env:" {
+		t.Errorf("Load() got prefix %q, want %q", cfg.Redis.Prefix, "env:")
+	}
+
+	cfg, err = config.Load([]string{"-config", path, "-redis-prefix", "flag:"})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if cfg.Redis.Prefix != "flag:" {
+		t.Errorf("Load() got prefix %q, want %q", cfg.Redis.Prefix, "flag:")
+	}
+}
+
+func TestLoadRedisConnection(t *testing.T) {
+	t.Setenv("REDIS_USERNAME", "cache")
+	t.Setenv("REDIS_TLS", "true")
+	t.Setenv("REDIS_POOL_SIZE", "20")
+
+	cfg, err := config.Load([]string{"-redis-max-retries", "-1", "-redis-dial-timeout", "1s", "-redis-tls-server-name", "redis.internal"})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	r := cfg.Redis
+	if r.Username != "cache" || !r.TLS.Enabled || r.TLS.ServerName != "redis.internal" {
+		t.Errorf("Load() got username %q, tls %+v", r.Username, r.TLS)
+	}
+	if r.Prefix != "cache:" {
+		t.Errorf("Load() got prefix %q, want the default", r.Prefix)
+	}
+	if r.PoolSize != 20 || r.MaxRetries != -1 || time.Duration(r.DialTimeout) != time.Second {
+		t.Errorf("Load() got pool size %d, max retries %d, dial timeout %s", r.PoolSize, r.MaxRetries, r.DialTimeout)
+	}
+}
+
+// writeCertificate writes a self-signed certificate and its key as PEM files.
+func writeCertificate(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestRedisTLSLoad(t *testing.T) {
+	if tlsConfig, err := (config.RedisTLSConfig{}).Load(); tlsConfig != nil || err != nil {
+		t.Errorf("Load() got %v, %v, want no TLS", tlsConfig, err)
+	}
+
+	certFile, keyFile := writeCertificate(t)
+	tlsConfig, err := config.RedisTLSConfig{Enabled: true, CAFile: certFile, CertFile: certFile, KeyFile: keyFile}.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 {
+		t.Errorf("Load() got %+v, want the CA and client certificate", tlsConfig)
+	}
+
+	// Negative Test Cases: a missing file and a CA file without certificates
+	if _, err := (config.RedisTLSConfig{Enabled: true, CertFile: "missing.pem", KeyFile: keyFile}).Load(); err == nil {
+		t.Error("Load() expected error for a missing certificate, got nil")
+	}
+	if _, err := (config.RedisTLSConfig{Enabled: true, CAFile: keyFile}).Load(); err == nil {
+		t.Error("Load() expected error for a CA file without certificates, got nil")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		file string
+	}{
+		{name: "zero size", args: []string{"-size", "0"}},
+		{name: "negative max bytes", args: []string{"-max-bytes", "-1"}},
+		{name: "zero janitor interval", env: map[string]string{"JANITOR_INTERVAL": "0"}},
+		{name: "bad ttl", env: map[string]string{"TTL": "soon"}},
+		{name: "bad db", env: map[string]string{"REDIS_DB": "one"}},
+		{name: "empty prefix", args: []string{"-redis-prefix", ""}},
+		{name: "prefix with braces", env: map[string]string{"REDIS_PREFIX": "{app}:"}},
+		{name: "master without sentinels", args: []string{"-redis-master-name", "mymaster"}},
+		{name: "cluster and sentinels", env: map[string]string{
+			"REDIS_MASTER_NAME":    "mymaster",
+			"REDIS_SENTINEL_ADDRS": "sentinel-1:26379",
+			"REDIS_CLUSTER_ADDRS":  "node-1:7000",
+		}},
+		{name: "cluster db", args: []string{"-redis-cluster-addrs", "node-1:7000", "-redis-db", "1"}},
+		{name: "tls files without tls", args: []string{"-redis-tls-ca-file", "ca.pem"}},
+		{name: "tls cert without key", args: []string{"-redis-tls", "-redis-tls-cert-file", "cert.pem"}},
+		{name: "bad tls flag", env: map[string]string{"REDIS_TLS": "maybe"}},
+		{name: "negative pool size", args: []string{"-redis-pool-size", "-1"}},
+		{name: "bad max retries", args: []string{"-redis-max-retries", "-2"}},
+		{name: "negative read timeout", env: map[string]string{"REDIS_READ_TIMEOUT": "-1s"}},
+		{name: "bad log sync", env: map[string]string{"LOG_SYNC": "sometimes"}},
+		{name: "negative snapshot interval", args: []string{"-snapshot-interval", "-1s"}},
+		{name: "shared port", args: []string{"-in-memory-port", "8080"}},
+		{name: "unknown flag", args: []string{"-colour", "blue"}},
+		{name: "unknown file key", file: "sizee: 3\n"},
+		{name: "missing file", args: []string{"-config", "does-not-exist.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "-config", writeFile(t, tt.file))
+			}
+			if _, err := config.Load(args); err == nil {
+				t.Errorf("Load() expected error, got nil")
+			}
+		})
+	}
+}
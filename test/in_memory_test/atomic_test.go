@@ -0,0 +1,154 @@
+package in_memory_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestIncrBy(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(10, time.Minute)
+	defer cache.Close()
+
+	// A missing key counts as zero
+	if n, err := cache.IncrBy("counter", 5); err != nil || n != 5 {
+		t.Fatalf("expected 5, got %d, %v", n, err)
+	}
+	if n, err := cache.Decr("counter"); err != nil || n != 4 {
+		t.Errorf("expected 4, got %d, %v", n, err)
+	}
+
+	// The stored value keeps its Go type
+	cache.Set("small", int32(7))
+	if n, err := cache.Incr("small"); err != nil || n != 8 {
+		t.Errorf("expected 8, got %d, %v", n, err)
+	}
+	if value, _ := cache.Get("small"); value != int32(8) {
+		t.Errorf("expected int32(8), got %T(%v)", value, value)
+	}
+	cache.Set("decoded", float64(2))
+	if n, err := cache.Incr("decoded"); err != nil || n != 3 {
+		t.Errorf("expected 3, got %d, %v", n, err)
+	}
+
+	// Negative Test Cases: values that are not integers, and overflow
+	for _, value := range []interface{}{"text", 1.5} {
+		cache.Set("bad", value)
+		if _, err := cache.Incr("bad"); !errors.Is(err, cachepkg.ErrNotInteger) {
+			t.Errorf("expected ErrNotInteger for %v, got %v", value, err)
+		}
+	}
+	cache.Set("byte", uint8(255))
+	if _, err := cache.Incr("byte"); !errors.Is(err, cachepkg.ErrNotInteger) {
+		t.Errorf("expected ErrNotInteger on overflow, got %v", err)
+	}
+}
+
+func TestIncrByKeepsTTL(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](10, time.Minute)
+	defer cache.Close()
+
+	cache.SetWithTTL("counter", 1, 50*time.Millisecond)
+	if _, err := cache.Incr("counter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := cache.Get("counter"); !errors.Is(err, in_memory.ErrCacheMiss) {
+		t.Errorf("expected the counter to expire with its original TTL, got %v", err)
+	}
+}
+
+func TestIncrByConcurrent(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int64]{
+		Config: in_memory.Config[string, int64]{MaxSize: 100, TTL: time.Minute},
+		Shards: 4,
+	})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Incr("counter")
+		}()
+	}
+	wg.Wait()
+	if value, _ := cache.Get("counter"); value != 50 {
+		t.Errorf("expected 50, got %d", value)
+	}
+}
+
+func TestSetNXAndReplace(t *testing.T) {
+	cache := in_memory.NewTyped[string, string](10, time.Minute)
+	defer cache.Close()
+
+	if ok, err := cache.Replace("key1", "value1", in_memory.DefaultExpiration); err != nil || ok {
+		t.Errorf("expected Replace of a missing key to do nothing, got %v, %v", ok, err)
+	}
+	if ok, err := cache.SetNX("key1", "value1", in_memory.DefaultExpiration); err != nil || !ok {
+		t.Errorf("expected SetNX to store, got %v, %v", ok, err)
+	}
+	if ok, _ := cache.SetNX("key1", "value2", in_memory.DefaultExpiration); ok {
+		t.Error("expected SetNX of an existing key to do nothing")
+	}
+	if value, _ := cache.Get("key1"); value != "value1" {
+		t.Errorf("expected value1, got %q", value)
+	}
+	if ok, err := cache.Replace("key1", "value3", in_memory.DefaultExpiration); err != nil || !ok {
+		t.Errorf("expected Replace to store, got %v, %v", ok, err)
+	}
+	if value, _ := cache.Get("key1"); value != "value3" {
+		t.Errorf("expected value3, got %q", value)
+	}
+
+	// An expired key counts as absent
+	cache.SetWithTTL("key2", "old", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if ok, _ := cache.SetNX("key2", "new", in_memory.DefaultExpiration); !ok {
+		t.Error("expected SetNX to store over an expired key")
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](10, time.Minute)
+	defer cache.Close()
+
+	cache.Set("key1", 1)
+	value, version, err := cache.GetVersioned("key1")
+	if err != nil || value != 1 {
+		t.Fatalf("expected 1, got %d, %v", value, err)
+	}
+
+	next, err := cache.CompareAndSwap("key1", 2, version, in_memory.DefaultExpiration)
+	if err != nil || next == version {
+		t.Fatalf("expected a new version, got %d, %v", next, err)
+	}
+
+	// Negative Test Case: the old version no longer matches
+	if _, err := cache.CompareAndSwap("key1", 3, version, in_memory.DefaultExpiration); !errors.Is(err, cachepkg.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+	if value, _ := cache.Get("key1"); value != 2 {
+		t.Errorf("expected 2, got %d", value)
+	}
+
+	// Negative Test Case: the key is gone
+	cache.Delete("key1")
+	if _, err := cache.CompareAndSwap("key1", 3, next, in_memory.DefaultExpiration); !errors.Is(err, cachepkg.ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+	if _, _, err := cache.GetVersioned("key1"); !errors.Is(err, cachepkg.ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	// A key deleted and written again never gets an old version back
+	cache.Set("key1", 4)
+	if _, again, _ := cache.GetVersioned("key1"); again == version || again == next {
+		t.Errorf("expected a fresh version, got %d again", again)
+	}
+}
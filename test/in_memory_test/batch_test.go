@@ -0,0 +1,89 @@
+package in_memory_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestMultiOperations(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](10, time.Minute)
+	defer cache.Close()
+
+	if err := cache.SetMulti(map[string]int{"key1": 1, "key2": 2, "key3": 3}, in_memory.DefaultExpiration); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values := cache.GetMulti([]string{"key1", "key3", "missing"})
+	if len(values) != 2 || values["key1"] != 1 || values["key3"] != 3 {
+		t.Errorf("expected key1 and key3, got %v", values)
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 1 || stats.Sets != 3 {
+		t.Errorf("expected 2 hits, 1 miss and 3 sets, got %+v", stats)
+	}
+
+	deleted := cache.DeleteMulti([]string{"key1", "key2", "missing"})
+	sort.Strings(deleted)
+	if len(deleted) != 2 || deleted[0] != "key1" || deleted[1] != "key2" {
+		t.Errorf("expected key1 and key2 deleted, got %v", deleted)
+	}
+	if keys := cache.GetAllKeys(); len(keys) != 1 || keys[0] != "key3" {
+		t.Errorf("expected only key3 left, got %v", keys)
+	}
+}
+
+func TestSetMultiIsAllOrNothing(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(10, time.Minute)
+	defer cache.Close()
+
+	// Negative Test Case: one nil value rejects the whole batch
+	err := cache.SetMulti(map[string]interface{}{"key1": 1, "key2": nil}, in_memory.DefaultExpiration)
+	if err == nil {
+		t.Fatal("expected an error for a nil value")
+	}
+	if cache.Exists("key1") {
+		t.Error("expected no items stored from a rejected batch")
+	}
+}
+
+func TestSetMultiEvicts(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](2, time.Minute)
+	defer cache.Close()
+
+	cache.Set("old", 0)
+	cache.SetMulti(map[string]int{"key1": 1, "key2": 2}, in_memory.DefaultExpiration)
+	if cache.Exists("old") || len(cache.GetAllKeys()) != 2 {
+		t.Errorf("expected the batch to evict the oldest key, got %v", cache.GetAllKeys())
+	}
+}
+
+func TestShardedMultiOperations(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int]{
+		Config: in_memory.Config[string, int]{TTL: time.Minute},
+		Shards: 4,
+	})
+	defer cache.Close()
+
+	items := make(map[string]int)
+	keys := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := "key" + strconv.Itoa(i)
+		items[key] = i
+		keys = append(keys, key)
+	}
+	if err := cache.SetMulti(items, in_memory.DefaultExpiration); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values := cache.GetMulti(keys); len(values) != 100 {
+		t.Errorf("expected 100 values across shards, got %d", len(values))
+	}
+	if deleted := cache.DeleteMulti(keys[:10]); len(deleted) != 10 {
+		t.Errorf("expected 10 deleted, got %d", len(deleted))
+	}
+	if n := len(cache.GetAllKeys()); n != 90 {
+		t.Errorf("expected 90 keys left, got %d", n)
+	}
+}
@@ -0,0 +1,58 @@
+package in_memory_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestCloseStopsCleanup(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	caches := make([]*in_memory.InMemoryCache, 20)
+	for i := range caches {
+		caches[i] = in_memory.NewInMemoryCache(3, time.Minute)
+	}
+	if runtime.NumGoroutine() < before+len(caches) {
+		t.Fatalf("expected one cleanup goroutine per cache")
+	}
+
+	for _, cache := range caches {
+		if err := cache.Close(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected cleanup goroutines to stop, %d before and %d after", before, after)
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, time.Minute)
+	cache.Close()
+	if err := cache.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// The cache stays usable after Close.
+	if err := cache.Set("key1", 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNoDefaultTTL(t *testing.T) {
+	// A cache without a default TTL must not panic when starting its cleanup.
+	cache := in_memory.NewInMemoryCache(3, in_memory.NoExpiration)
+	defer cache.Close()
+
+	cache.Set("key1", 1)
+	ttl, err := cache.TTL("key1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl != in_memory.NoExpiration {
+		t.Errorf("expected NoExpiration, got %v", ttl)
+	}
+}
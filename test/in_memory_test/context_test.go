@@ -0,0 +1,51 @@
+package in_memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestContextVariants(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+	ctx := context.Background()
+
+	if err := cache.SetCtx(ctx, "key1", 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, err := cache.GetCtx(ctx, "key1")
+	if err != nil || value != 100 {
+		t.Errorf("expected 100, nil; got %v, %v", value, err)
+	}
+	if ok, err := cache.ExistsCtx(ctx, "key1"); !ok || err != nil {
+		t.Errorf("expected key1 to exist, got %v, %v", ok, err)
+	}
+	if err := cache.DeleteCtx(ctx, "key1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestContextDone(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+
+	// Negative Test Case: a canceled context stops the operation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cache.SetCtx(ctx, "key1", 100); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if cache.Exists("key1") {
+		t.Error("expected key1 not to be set")
+	}
+
+	// Negative Test Case: an expired deadline is reported as a timeout
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := cache.GetCtx(ctx, "key1"); !errors.Is(err, cachepkg.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
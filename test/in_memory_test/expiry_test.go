@@ -0,0 +1,86 @@
+package in_memory_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+// waitForEntries polls until the cache holds want entries or a second passes.
+func waitForEntries[K comparable, V any](t *testing.T, cache *in_memory.TypedCache[K, V], want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cache.Stats().Entries == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d entries, got %d", want, cache.Stats().Entries)
+}
+
+func TestJanitorRemovesExpiredEntries(t *testing.T) {
+	// The janitor runs far more often than the TTL.
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{TTL: time.Hour, JanitorInterval: 10 * time.Millisecond})
+	defer cache.Close()
+
+	cache.SetWithTTL("short", 1, 20*time.Millisecond)
+	cache.Set("long", 2)
+	cache.SetWithTTL("forever", 3, in_memory.NoExpiration)
+
+	// No Get is needed for "short" to go.
+	waitForEntries(t, cache, 2)
+	if !cache.Exists("long") || !cache.Exists("forever") {
+		t.Error("expected unexpired entries to remain")
+	}
+}
+
+func TestJanitorManyExpiries(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[int, int]{TTL: 20 * time.Millisecond, JanitorInterval: 10 * time.Millisecond})
+	defer cache.Close()
+
+	// Far more than one batch expires at once.
+	for key := 1; key <= 10000; key++ {
+		cache.Set(key, key)
+	}
+	waitForEntries(t, cache, 0)
+}
+
+func TestJanitorFollowsExpireAndPersist(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{TTL: 20 * time.Millisecond, JanitorInterval: 5 * time.Millisecond})
+	defer cache.Close()
+
+	for i := 0; i < 3; i++ {
+		cache.Set("key"+strconv.Itoa(i), i)
+	}
+	cache.Persist("key0")
+	cache.Expire("key1", time.Hour)
+	cache.Set("key2", 2) // Overwriting keeps the default TTL
+
+	waitForEntries(t, cache, 2)
+	if !cache.Exists("key0") || !cache.Exists("key1") {
+		t.Error("expected the persisted and extended keys to remain")
+	}
+
+	// Shortening a TTL brings the removal forward.
+	cache.Expire("key1", 10*time.Millisecond)
+	waitForEntries(t, cache, 1)
+}
+
+func TestExpiredEntryNotReturnedBeforeJanitor(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{TTL: time.Hour, JanitorInterval: time.Hour})
+	defer cache.Close()
+
+	cache.SetWithTTL("key", 1, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// Negative Test Case: the janitor has not run, but the entry has expired
+	if _, err := cache.Get("key"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+	if cache.Stats().Entries != 0 {
+		t.Error("expected the lookup to remove the expired entry")
+	}
+}
@@ -0,0 +1,124 @@
+package in_memory_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+type evictionRecord struct {
+	key    string
+	value  int
+	reason cachepkg.EvictionReason
+}
+
+// recorder collects the events passed to an OnEvict hook.
+type recorder struct {
+	lock   sync.Mutex
+	events []evictionRecord
+}
+
+func (r *recorder) hook(key string, value int, reason cachepkg.EvictionReason) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, evictionRecord{key, value, reason})
+}
+
+func (r *recorder) get() []evictionRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]evictionRecord(nil), r.events...)
+}
+
+func TestOnEvictReasons(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](2, 5*time.Minute)
+	defer cache.Close()
+	var rec recorder
+	cache.OnEvict(rec.hook)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3) // Evicts "a"
+	cache.Set("c", 4) // Overwriting is not an eviction
+	cache.Delete("b")
+	cache.SetWithTTL("d", 5, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	cache.Get("d")
+	cache.DeleteAll()
+
+	want := []evictionRecord{
+		{"a", 1, cachepkg.ReasonCapacity},
+		{"b", 2, cachepkg.ReasonDeleted},
+		{"d", 5, cachepkg.ReasonExpired},
+		{"c", 4, cachepkg.ReasonCleared},
+	}
+	got := rec.get()
+	if len(got) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestOnEvictFromJanitor(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{TTL: 10 * time.Millisecond, JanitorInterval: 5 * time.Millisecond})
+	defer cache.Close()
+	var rec recorder
+	cache.OnEvict(rec.hook)
+
+	cache.Set("a", 1)
+	waitForEntries(t, cache, 0)
+
+	if got := rec.get(); len(got) != 1 || got[0] != (evictionRecord{"a", 1, cachepkg.ReasonExpired}) {
+		t.Errorf("expected one expiry of a, got %v", got)
+	}
+}
+
+func TestOnEvictRunsOutsideLock(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](1, 5*time.Minute)
+	defer cache.Close()
+
+	// A hook that uses the cache would deadlock if it ran under the lock.
+	var reinserted bool
+	cache.OnEvict(func(key string, value int, reason cachepkg.EvictionReason) {
+		if reason == cachepkg.ReasonDeleted && !cache.Exists(key) {
+			reinserted = cache.Set(key+"-copy", value) == nil
+		}
+	})
+
+	cache.Set("a", 1)
+	cache.Delete("a")
+	if !reinserted || !cache.Exists("a-copy") {
+		t.Error("expected the hook to write to the cache")
+	}
+}
+
+func TestShardedOnEvict(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int]{Config: in_memory.Config[string, int]{TTL: 5 * time.Minute}, Shards: 4})
+	defer cache.Close()
+	var rec recorder
+	cache.OnEvict(rec.hook)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		cache.Set(key, 1)
+		cache.Delete(key)
+	}
+	if got := rec.get(); len(got) != 4 {
+		t.Errorf("expected 4 delete events, got %v", got)
+	}
+}
+
+func TestEvictionReasonString(t *testing.T) {
+	if got := cachepkg.ReasonCapacity.String(); got != "capacity" {
+		t.Errorf("expected capacity, got %s", got)
+	}
+	if got := cachepkg.EvictionReason(0).String(); got != "EvictionReason(0)" {
+		t.Errorf("expected EvictionReason(0), got %s", got)
+	}
+}
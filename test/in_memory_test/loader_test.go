@@ -0,0 +1,148 @@
+package in_memory_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestGetOrLoadCoalescesMisses(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](10, time.Minute)
+	defer cache.Close()
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetOrLoad(ctx, "hot", loader)
+			if err == nil && value != 42 {
+				err = fmt.Errorf("expected 42, got %d", value)
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // Let the callers pile up on the load
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected one loader call, got %d", n)
+	}
+
+	// The loaded value is cached
+	if value, err := cache.Get("hot"); err != nil || value != 42 {
+		t.Errorf("expected cached 42, got %d, %v", value, err)
+	}
+}
+
+func TestGetOrLoadNegativeCaching(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		MaxSize:     10,
+		TTL:         time.Minute,
+		NegativeTTL: 50 * time.Millisecond,
+	})
+	defer cache.Close()
+	ctx := context.Background()
+
+	var calls int
+	notFound := func() (int, error) {
+		calls++
+		return 0, fmt.Errorf("no row: %w", in_memory.ErrCacheMiss)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetOrLoad(ctx, "absent", notFound); err != in_memory.ErrCacheMiss {
+			t.Fatalf("expected ErrCacheMiss, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the miss to be remembered, got %d loader calls", calls)
+	}
+
+	// A value stored explicitly is served despite the negative entry
+	cache.Set("absent", 7)
+	if value, err := cache.GetOrLoad(ctx, "absent", notFound); err != nil || value != 7 {
+		t.Errorf("expected 7, got %d, %v", value, err)
+	}
+	cache.Delete("absent")
+
+	time.Sleep(60 * time.Millisecond)
+	cache.GetOrLoad(ctx, "absent", notFound)
+	if calls != 2 {
+		t.Errorf("expected the negative entry to expire, got %d loader calls", calls)
+	}
+}
+
+func TestGetOrLoadErrorsAreNotCached(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		MaxSize:     10,
+		NegativeTTL: time.Minute,
+	})
+	defer cache.Close()
+	ctx := context.Background()
+
+	errDown := errors.New("database down")
+	var calls int
+	failing := func() (int, error) {
+		calls++
+		return 0, errDown
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetOrLoad(ctx, "key1", failing); !errors.Is(err, errDown) {
+			t.Fatalf("expected the loader's error, got %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected failed loads to be retried, got %d loader calls", calls)
+	}
+	if cache.Exists("key1") {
+		t.Error("expected nothing cached after a failed load")
+	}
+}
+
+func TestGetOrLoadWaiterHonoursContext(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](10, time.Minute)
+	defer cache.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go cache.GetOrLoad(context.Background(), "slow", func() (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.GetOrLoad(ctx, "slow", func() (int, error) { return 2, nil }); !errors.Is(err, cachepkg.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package in_memory_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func logConfig(path string) in_memory.Config[string, int] {
+	return in_memory.Config[string, int]{MaxSize: 10, TTL: time.Minute, LogPath: path, LogSync: in_memory.SyncAlways}
+}
+
+// crashed leaves cache open until the end of the test, as if its process had
+// died: nothing is saved beyond what the log already holds.
+func crashed(t *testing.T, cache *in_memory.TypedCache[string, int]) {
+	t.Cleanup(func() { cache.Close() })
+}
+
+func TestLogReplayAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cache := in_memory.NewWithConfig(logConfig(path))
+	crashed(t, cache)
+	cache.Set("gone", 0)
+	cache.DeleteAll()
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Set("key3", 3)
+	cache.Delete("key2")
+	cache.Incr("key3")
+	cache.SetWithTTL("key4", 4, 10*time.Millisecond)
+	cache.Persist("key4")
+	cache.SetWithTTL("short", 5, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	restarted := in_memory.NewWithConfig(logConfig(path))
+	defer restarted.Close()
+	for key, want := range map[string]int{"key1": 1, "key3": 4, "key4": 4} {
+		if value, err := restarted.Get(key); err != nil || value != want {
+			t.Errorf("expected %s = %d, got %d, %v", key, want, value, err)
+		}
+	}
+	for _, key := range []string{"gone", "key2", "short"} {
+		if _, err := restarted.Get(key); err == nil {
+			t.Errorf("expected %s to be missing", key)
+		}
+	}
+	if ttl, _ := restarted.TTL("key4"); ttl != in_memory.NoExpiration {
+		t.Errorf("expected the Persist to be replayed, got %s", ttl)
+	}
+}
+
+func TestLogReplayDropsTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cache := in_memory.NewWithConfig(logConfig(path))
+	crashed(t, cache)
+	cache.Set("key1", 1)
+
+	// Negative Test Case: a record cut short by the crash
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Write([]byte{0, 0, 0, 40, 1, 2})
+	f.Close()
+
+	var reported error
+	cfg := logConfig(path)
+	cfg.OnSnapshotError = func(err error) { reported = err }
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	if value, err := restarted.Get("key1"); err != nil || value != 1 {
+		t.Errorf("expected key1 restored, got %d, %v", value, err)
+	}
+	if reported != nil {
+		t.Errorf("expected a torn record to be dropped silently, got %v", reported)
+	}
+
+	// Writes after the restart follow the intact records.
+	restarted.Set("key2", 2)
+	again := in_memory.NewWithConfig(logConfig(path))
+	crashed(t, again)
+	if value, err := again.Get("key2"); err != nil || value != 2 {
+		t.Errorf("expected key2 restored, got %d, %v", value, err)
+	}
+}
+
+func TestLogUnreadableRecordIsSetAside(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cache := in_memory.NewWithConfig(logConfig(path))
+	crashed(t, cache)
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	before, _ := os.ReadFile(path)
+
+	// Negative Test Case: records written for another value type
+	var reported error
+	cfg := in_memory.Config[string, string]{MaxSize: 10, TTL: time.Minute, LogPath: path, LogSync: in_memory.SyncAlways}
+	cfg.OnSnapshotError = func(err error) { reported = err }
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	if reported == nil {
+		t.Error("expected the undecodable record to be reported")
+	}
+
+	aside, _ := filepath.Glob(path + ".corrupt-*")
+	if len(aside) != 1 {
+		t.Fatalf("expected the log to be set aside, found %v", aside)
+	}
+	if after, _ := os.ReadFile(aside[0]); string(after) != string(before) {
+		t.Errorf("expected the set-aside log to keep all %d bytes, has %d", len(before), len(after))
+	}
+
+	// The cache keeps logging to a fresh file.
+	restarted.Set("key3", "three")
+	again := in_memory.NewWithConfig(cfg)
+	t.Cleanup(func() { again.Close() })
+	if value, err := again.Get("key3"); err != nil || value != "three" {
+		t.Errorf("expected key3 restored, got %q, %v", value, err)
+	}
+}
+
+func TestLogReplayIsNotCounted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cache := in_memory.NewWithConfig(logConfig(path))
+	crashed(t, cache)
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Delete("key1")
+
+	restarted := in_memory.NewWithConfig(logConfig(path))
+	defer restarted.Close()
+	stats := restarted.Stats()
+	if stats.Sets != 0 || stats.Deletes != 0 || stats.Evictions != (cachepkg.EvictionStats{}) {
+		t.Errorf("expected replayed records not to be counted, got %+v", stats)
+	}
+	if stats.Entries != 1 {
+		t.Errorf("expected 1 entry restored, got %d", stats.Entries)
+	}
+}
+
+func TestLogReplaySkipsExpiredEntriesWhenFull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cfg := logConfig(path)
+	cfg.MaxSize = 2
+	cfg.JanitorInterval = time.Millisecond
+	cache := in_memory.NewWithConfig(cfg)
+	crashed(t, cache)
+	cache.Set("live1", 1)
+	cache.SetWithTTL("dead", 0, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // The janitor expires dead, which is not logged
+	cache.Set("live2", 2)
+
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	for key, want := range map[string]int{"live1": 1, "live2": 2} {
+		if value, err := restarted.Get(key); err != nil || value != want {
+			t.Errorf("expected %s = %d, got %d, %v", key, want, value, err)
+		}
+	}
+	if _, err := restarted.Get("dead"); err == nil {
+		t.Error("expected dead to be missing")
+	}
+}
+
+func TestLogCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cfg := logConfig(path)
+	cache := in_memory.NewWithConfig(cfg)
+	crashed(t, cache)
+	for i := 0; i < 100; i++ {
+		cache.Set("counter", i)
+	}
+	before, _ := os.Stat(path)
+	if err := cache.CompactLog(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, _ := os.Stat(path)
+	if after.Size() != 0 || before.Size() == 0 {
+		t.Errorf("expected the log emptied, had %d bytes, now %d", before.Size(), after.Size())
+	}
+	if _, err := os.Stat(path + ".snapshot"); err != nil {
+		t.Errorf("expected a snapshot next to the log, got %v", err)
+	}
+	cache.Set("key1", 1)
+
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	if value, _ := restarted.Get("counter"); value != 99 {
+		t.Errorf("expected the snapshot restored, got %d", value)
+	}
+	if value, _ := restarted.Get("key1"); value != 1 {
+		t.Errorf("expected the log replayed over the snapshot, got %d", value)
+	}
+}
+
+func TestLogCompactsWhenLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cfg := logConfig(path)
+	cfg.LogCompactSize = 1024
+	cache := in_memory.NewWithConfig(cfg)
+	defer cache.Close()
+	for i := 0; i < 100; i++ {
+		cache.Set("counter", i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(path + ".snapshot"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected a compaction once the log passed its limit")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLogCloseCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+
+	cfg := logConfig(path)
+	cfg.LogSync = in_memory.SyncEverySecond
+	cache := in_memory.NewWithConfig(cfg)
+	cache.Set("key1", 1)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Errorf("expected an empty log after Close, got %v, %v", info, err)
+	}
+
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	if value, err := restarted.Get("key1"); err != nil || value != 1 {
+		t.Errorf("expected key1 restored, got %d, %v", value, err)
+	}
+}
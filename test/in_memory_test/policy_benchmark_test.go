@@ -0,0 +1,83 @@
+package in_memory_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+const (
+	traceLength   = 200000
+	traceKeySpace = 10000
+	traceCapacity = 500
+)
+
+// Traces are generated from fixed seeds so every run replays the same accesses.
+var traces = map[string][]int{
+	"Zipf":     zipfTrace(1),
+	"Loop":     loopTrace(),
+	"ZipfScan": zipfScanTrace(2),
+}
+
+// zipfTrace models a skewed workload where a few keys take most reads.
+func zipfTrace(seed int64) []int {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.1, 1, traceKeySpace-1)
+	trace := make([]int, traceLength)
+	for i := range trace {
+		trace[i] = int(zipf.Uint64()) + 1 // Zero keys are rejected by the cache
+	}
+	return trace
+}
+
+// loopTrace cycles through slightly more keys than fit, the worst case for LRU and FIFO.
+func loopTrace() []int {
+	trace := make([]int, traceLength)
+	for i := range trace {
+		trace[i] = i%(traceCapacity*3/2) + 1
+	}
+	return trace
+}
+
+// zipfScanTrace is a Zipf workload interrupted by scans over keys read only once.
+func zipfScanTrace(seed int64) []int {
+	trace := zipfTrace(seed)
+	next := traceKeySpace + 1
+	for start := 0; start < len(trace); start += 10000 {
+		for i := start; i < start+2000 && i < len(trace); i++ {
+			trace[i] = next
+			next++
+		}
+	}
+	return trace
+}
+
+// BenchmarkPolicyHitRatio replays each trace against each policy, filling the
+// cache on misses, and reports the fraction of reads that hit.
+func BenchmarkPolicyHitRatio(b *testing.B) {
+	for traceName, trace := range traces {
+		for policyName, factory := range policies {
+			b.Run(traceName+"/"+policyName, func(b *testing.B) {
+				cache := in_memory.NewWithConfig(in_memory.Config[int, int]{
+					MaxSize: traceCapacity,
+					TTL:     5 * time.Minute,
+					Policy:  factory,
+				})
+				defer cache.Close()
+
+				hits := 0
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					key := trace[i%len(trace)]
+					if _, err := cache.Get(key); err == nil {
+						hits++
+					} else {
+						cache.Set(key, key)
+					}
+				}
+				b.ReportMetric(float64(hits)/float64(b.N), "hit-ratio")
+			})
+		}
+	}
+}
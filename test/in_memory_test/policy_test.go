@@ -0,0 +1,133 @@
+package in_memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+var policies = map[string]in_memory.PolicyFactory[int]{
+	"LRU":     in_memory.NewLRU[int],
+	"LFU":     in_memory.NewLFU[int],
+	"FIFO":    in_memory.NewFIFO[int],
+	"ARC":     in_memory.NewARC[int],
+	"TinyLFU": in_memory.NewTinyLFU[int],
+}
+
+func TestPolicyEvictRemoveReset(t *testing.T) {
+	for name, factory := range policies {
+		t.Run(name, func(t *testing.T) {
+			policy := factory(10)
+
+			// Negative Test Case: nothing to evict
+			if _, ok := policy.Evict(); ok {
+				t.Fatal("expected no victim from an empty policy")
+			}
+
+			for key := 1; key <= 3; key++ {
+				policy.Add(key)
+			}
+			policy.Remove(2)
+
+			evicted := map[int]bool{}
+			for {
+				key, ok := policy.Evict()
+				if !ok {
+					break
+				}
+				if evicted[key] {
+					t.Fatalf("key %d evicted twice", key)
+				}
+				evicted[key] = true
+			}
+			if len(evicted) != 2 || !evicted[1] || !evicted[3] {
+				t.Errorf("expected keys 1 and 3 to be evicted, got %v", evicted)
+			}
+
+			policy.Add(4)
+			policy.Reset()
+			if _, ok := policy.Evict(); ok {
+				t.Error("expected no victim after Reset")
+			}
+		})
+	}
+}
+
+func TestFIFOIgnoresReads(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{MaxSize: 2, TTL: 5 * time.Minute, Policy: in_memory.NewFIFO[string]})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")    // Would save "a" under LRU
+	cache.Set("c", 3) // Evicts "a", the oldest insert
+
+	if cache.Exists("a") {
+		t.Error("expected a to be evicted")
+	}
+	if !cache.Exists("b") || !cache.Exists("c") {
+		t.Error("expected b and c to remain")
+	}
+}
+
+func TestLFUKeepsFrequentKeys(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{MaxSize: 2, TTL: 5 * time.Minute, Policy: in_memory.NewLFU[string]})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")    // "b" is now the most recent but less frequent
+	cache.Set("c", 3) // Evicts "b"
+
+	if cache.Exists("b") {
+		t.Error("expected b to be evicted")
+	}
+	if !cache.Exists("a") || !cache.Exists("c") {
+		t.Error("expected a and c to remain")
+	}
+}
+
+// TestScanResistance checks that a one-off scan larger than the cache does not
+// flush keys that are read repeatedly, which is exactly what LRU does.
+func TestScanResistance(t *testing.T) {
+	for _, name := range []string{"LFU", "ARC", "TinyLFU"} {
+		t.Run(name, func(t *testing.T) {
+			cache := in_memory.NewWithConfig(in_memory.Config[int, int]{MaxSize: 100, TTL: 5 * time.Minute, Policy: policies[name]})
+			defer cache.Close()
+
+			for key := 1; key <= 50; key++ {
+				cache.Set(key, key)
+				for i := 0; i < 4; i++ {
+					cache.Get(key)
+				}
+			}
+			for key := 1000; key < 1300; key++ {
+				cache.Set(key, key)
+			}
+
+			for key := 1; key <= 50; key++ {
+				if !cache.Exists(key) {
+					t.Fatalf("hot key %d was evicted by the scan", key)
+				}
+			}
+			if n := len(cache.GetAllKeys()); n != 100 {
+				t.Errorf("expected 100 keys, got %d", n)
+			}
+		})
+	}
+}
+
+func TestUnlimitedSize(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[int, int]{TTL: 5 * time.Minute})
+	defer cache.Close()
+
+	for key := 1; key <= 1000; key++ {
+		cache.Set(key, key)
+	}
+	if n := len(cache.GetAllKeys()); n != 1000 {
+		t.Errorf("expected 1000 keys, got %d", n)
+	}
+}
@@ -0,0 +1,151 @@
+package in_memory_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+// gatedLoader returns 100 plus the number of calls so far, once each call
+// is released.
+type gatedLoader struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func newGatedLoader() *gatedLoader {
+	return &gatedLoader{release: make(chan struct{}, 100)}
+}
+
+func (l *gatedLoader) load(key string) (int, error) {
+	n := l.calls.Add(1)
+	<-l.release
+	return 100 + int(n), nil
+}
+
+// waitForValue polls Get until it returns want or a second passes.
+func waitForValue(t *testing.T, cache *in_memory.TypedCache[string, int], key string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if value, err := cache.Get(key); err == nil && value == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	value, err := cache.Get(key)
+	t.Fatalf("expected %d, got %d, %v", want, value, err)
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	loader := newGatedLoader()
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		TTL:     time.Minute,
+		SoftTTL: 20 * time.Millisecond,
+		Loader:  loader.load,
+	})
+	defer cache.Close()
+
+	cache.Set("key1", 1)
+	if value, _ := cache.Get("key1"); value != 1 || loader.calls.Load() != 0 {
+		t.Fatalf("expected a fresh read without refresh, got %d after %d loads", value, loader.calls.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	// Stale reads return the old value and share one refresh
+	for i := 0; i < 5; i++ {
+		if value, err := cache.Get("key1"); err != nil || value != 1 {
+			t.Fatalf("expected stale value 1, got %d, %v", value, err)
+		}
+	}
+	loader.release <- struct{}{}
+	waitForValue(t, cache, "key1", 101)
+	if n := loader.calls.Load(); n != 1 {
+		t.Errorf("expected one refresh, got %d", n)
+	}
+}
+
+func TestRefreshAheadOnlyForHotKeys(t *testing.T) {
+	loader := newGatedLoader()
+	close(loader.release)
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		TTL:              100 * time.Millisecond,
+		RefreshAhead:     60 * time.Millisecond,
+		RefreshAheadHits: 2,
+		Loader:           loader.load,
+	})
+	defer cache.Close()
+
+	cache.Set("hot", 1)
+	cache.Set("cold", 2)
+	cache.Get("hot") // Read outside the window: counts but does not refresh
+	if n := loader.calls.Load(); n != 0 {
+		t.Fatalf("expected no refresh outside the window, got %d", n)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	cache.Get("hot")
+	cache.Get("cold")
+	waitForValue(t, cache, "hot", 101)
+
+	time.Sleep(70 * time.Millisecond) // Past the original TTL
+	if value, err := cache.Get("hot"); err != nil || value != 101 {
+		t.Errorf("expected the refreshed hot key to outlive its first TTL, got %d, %v", value, err)
+	}
+	if _, err := cache.Get("cold"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected the cold key to expire, got %v", err)
+	}
+	if n := loader.calls.Load(); n != 1 {
+		t.Errorf("expected one refresh, got %d", n)
+	}
+}
+
+func TestRefreshDoesNotOverwriteNewerValue(t *testing.T) {
+	loader := newGatedLoader()
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		TTL:     time.Minute,
+		SoftTTL: 10 * time.Millisecond,
+		Loader:  loader.load,
+	})
+	defer cache.Close()
+
+	cache.Set("key1", 1)
+	time.Sleep(20 * time.Millisecond)
+	cache.Get("key1") // Starts a refresh
+	cache.Set("key1", 9)
+	cache.Set("key2", 5)
+	cache.Delete("key2")
+	loader.release <- struct{}{}
+	cache.Close() // Waits for the refresh
+
+	if value, _ := cache.Get("key1"); value != 9 {
+		t.Errorf("expected the newer value 9 to survive the refresh, got %d", value)
+	}
+}
+
+func TestRefreshFailureKeepsStaleValue(t *testing.T) {
+	var calls atomic.Int32
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		TTL:     time.Minute,
+		SoftTTL: 10 * time.Millisecond,
+		Loader: func(key string) (int, error) {
+			calls.Add(1)
+			return 0, errors.New("database down")
+		},
+	})
+
+	cache.Set("key1", 1)
+	time.Sleep(20 * time.Millisecond)
+	cache.Get("key1")
+	cache.Close()
+
+	if value, err := cache.Get("key1"); err != nil || value != 1 {
+		t.Errorf("expected stale value 1 after a failed refresh, got %d, %v", value, err)
+	}
+	if calls.Load() == 0 {
+		t.Error("expected a refresh attempt")
+	}
+}
@@ -0,0 +1,75 @@
+package in_memory_test
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+// Run with -cpu 1,2,4,8 to compare how the single-lock and sharded caches
+// scale with GOMAXPROCS.
+
+const parallelKeys = 10000
+
+var parallelKeyNames = func() []string {
+	keys := make([]string, parallelKeys)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}()
+
+// parallelStore is the part of the API shared by both cache types.
+type parallelStore interface {
+	Set(key string, value int) error
+	Get(key string) (int, error)
+	Close() error
+}
+
+func newParallelCaches() map[string]func() parallelStore {
+	cfg := in_memory.Config[string, int]{MaxSize: parallelKeys, TTL: 5 * time.Minute}
+	return map[string]func() parallelStore{
+		"SingleLock": func() parallelStore { return in_memory.NewWithConfig(cfg) },
+		"Sharded":    func() parallelStore { return in_memory.NewSharded(in_memory.ShardedConfig[string, int]{Config: cfg}) },
+	}
+}
+
+// benchmarkParallel runs a mix of reads and writes from every P, with one
+// write every writeEvery operations.
+func benchmarkParallel(b *testing.B, writeEvery int) {
+	for name, newCache := range newParallelCaches() {
+		b.Run(name, func(b *testing.B) {
+			cache := newCache()
+			defer cache.Close()
+			for i, key := range parallelKeyNames {
+				cache.Set(key, i)
+			}
+
+			var worker atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(worker.Add(1)) * 7919 // Start each worker elsewhere in the key space
+				for pb.Next() {
+					key := parallelKeyNames[i%parallelKeys]
+					if i%writeEvery == 0 {
+						cache.Set(key, i)
+					} else {
+						cache.Get(key)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkParallelReadHeavy(b *testing.B) {
+	benchmarkParallel(b, 10)
+}
+
+func BenchmarkParallelWriteHeavy(b *testing.B) {
+	benchmarkParallel(b, 2)
+}
@@ -0,0 +1,120 @@
+package in_memory_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestShardedSetGetDelete(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int]{Config: in_memory.Config[string, int]{TTL: 5 * time.Minute}, Shards: 8})
+	defer cache.Close()
+
+	for i := 1; i <= 100; i++ {
+		if err := cache.Set(strconv.Itoa(i), i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for i := 1; i <= 100; i++ {
+		value, err := cache.Get(strconv.Itoa(i))
+		if err != nil || value != i {
+			t.Fatalf("Get(%d) = %d, %v", i, value, err)
+		}
+	}
+	if n := len(cache.GetAllKeys()); n != 100 {
+		t.Errorf("expected 100 keys, got %d", n)
+	}
+
+	cache.Delete("1")
+	if cache.Exists("1") {
+		t.Error("expected 1 to be deleted")
+	}
+	// Negative Test Case: deleting a missing key
+	if err := cache.Delete("1"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	cache.DeleteAll()
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected an empty cache, got %+v", stats)
+	}
+}
+
+func TestShardedCapacity(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[int, int]{
+		Config: in_memory.Config[int, int]{MaxSize: 64, TTL: 5 * time.Minute},
+		Shards: 4,
+		// Send every key to the same shard to show capacity is per shard.
+		Hasher: func(key int) uint64 { return 0 },
+	})
+	defer cache.Close()
+
+	for key := 1; key <= 64; key++ {
+		cache.Set(key, key)
+	}
+	if stats := cache.Stats(); stats.Entries != 16 {
+		t.Errorf("expected one shard of 16 entries, got %+v", stats)
+	}
+	// The shard evicts by LRU, keeping the newest keys.
+	if !cache.Exists(64) || cache.Exists(1) {
+		t.Error("expected the shard to keep the most recent keys")
+	}
+}
+
+func TestShardedExpiry(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, string]{Config: in_memory.Config[string, string]{TTL: 5 * time.Minute}})
+	defer cache.Close()
+
+	cache.SetWithTTL("short", "value", 10*time.Millisecond)
+	cache.Set("long", "value")
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := cache.Get("short"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss for an expired key, got %v", err)
+	}
+	if ttl, err := cache.TTL("long"); err != nil || ttl <= 0 {
+		t.Errorf("TTL(long) = %v, %v", ttl, err)
+	}
+}
+
+func TestShardedConcurrentAccess(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int]{Config: in_memory.Config[string, int]{MaxSize: 1000, TTL: 5 * time.Minute}})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := strconv.Itoa(g*1000 + i)
+				cache.Set(key, i)
+				cache.Get(key)
+				cache.Exists(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Entries > 1000 {
+		t.Errorf("expected at most 1000 entries, got %d", stats.Entries)
+	}
+}
+
+func TestShardedBackend(t *testing.T) {
+	backend := in_memory.NewShardedBackend(in_memory.NewSharded(in_memory.ShardedConfig[string, interface{}]{Config: in_memory.Config[string, interface{}]{TTL: 5 * time.Minute}}))
+	defer backend.Close()
+	ctx := context.Background()
+
+	if err := backend.Set(ctx, "key", "value", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, err := backend.Get(ctx, "key")
+	if err != nil || value != "value" {
+		t.Errorf("Get() = %v, %v", value, err)
+	}
+}
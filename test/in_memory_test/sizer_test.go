@@ -0,0 +1,133 @@
+package in_memory_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+// lenSizer charges each entry the length of its value.
+func lenSizer(key string, value string) int64 {
+	return int64(len(value))
+}
+
+func TestEstimateSize(t *testing.T) {
+	if got := in_memory.EstimateSize(nil); got != 0 {
+		t.Errorf("EstimateSize(nil) = %d, want 0", got)
+	}
+	if small, big := in_memory.EstimateSize("a"), in_memory.EstimateSize(strings.Repeat("a", 1000)); big-small != 999 {
+		t.Errorf("expected strings to differ by their length, got %d and %d", small, big)
+	}
+	if got := in_memory.EstimateSize(make([]byte, 1000)); got < 1000 {
+		t.Errorf("EstimateSize([]byte) = %d, want at least 1000", got)
+	}
+
+	// Values reached through maps, slices and pointers are counted.
+	nested := map[string]interface{}{"list": []string{strings.Repeat("x", 500)}, "user": &user{Name: strings.Repeat("y", 500)}}
+	if got := in_memory.EstimateSize(nested); got < 1000 {
+		t.Errorf("EstimateSize(nested) = %d, want at least 1000", got)
+	}
+
+	// Cycles terminate.
+	type node struct{ next *node }
+	n := &node{}
+	n.next = n
+	if got := in_memory.EstimateSize(n); got <= 0 {
+		t.Errorf("EstimateSize(cycle) = %d, want a positive size", got)
+	}
+}
+
+func TestByteBudgetEviction(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, string]{MaxBytes: 1000, TTL: 5 * time.Minute, Sizer: lenSizer})
+	defer cache.Close()
+
+	cache.Set("a", strings.Repeat("a", 400))
+	cache.Set("b", strings.Repeat("b", 400))
+	cache.Set("c", strings.Repeat("c", 400)) // This should evict "a"
+
+	if cache.Exists("a") {
+		t.Error("expected a to be evicted")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Bytes != 800 || stats.MaxBytes != 1000 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// Many small values fit where a few large ones did not.
+	for _, key := range []string{"d", "e", "f"} {
+		cache.Set(key, "tiny")
+	}
+	if n := len(cache.GetAllKeys()); n != 5 {
+		t.Errorf("expected 5 keys, got %d", n)
+	}
+}
+
+func TestByteBudgetUpdate(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, string]{MaxBytes: 1000, TTL: 5 * time.Minute, Sizer: lenSizer})
+	defer cache.Close()
+
+	cache.Set("a", strings.Repeat("a", 400))
+	cache.Set("b", strings.Repeat("b", 400))
+
+	// Growing "b" pushes the cache over budget, evicting "a".
+	cache.Set("b", strings.Repeat("b", 900))
+	if cache.Exists("a") {
+		t.Error("expected a to be evicted")
+	}
+	if got := cache.Stats().Bytes; got != 900 {
+		t.Errorf("expected 900 bytes, got %d", got)
+	}
+
+	cache.Delete("b")
+	if got := cache.Stats().Bytes; got != 0 {
+		t.Errorf("expected 0 bytes after delete, got %d", got)
+	}
+}
+
+func TestByteBudgetTooLarge(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, string]{MaxBytes: 100, TTL: 5 * time.Minute, Sizer: lenSizer})
+	defer cache.Close()
+
+	cache.Set("a", "small")
+
+	// Negative Test Case: an entry bigger than the whole budget is rejected
+	if err := cache.Set("big", strings.Repeat("x", 101)); err != in_memory.ErrEntryTooLarge {
+		t.Errorf("expected ErrEntryTooLarge, got %v", err)
+	}
+	if !cache.Exists("a") {
+		t.Error("expected a rejected entry to leave the cache untouched")
+	}
+}
+
+func TestByteBudgetDefaultSizer(t *testing.T) {
+	cache := in_memory.NewWithConfig(in_memory.Config[string, interface{}]{MaxSize: 100, MaxBytes: 4096, TTL: 5 * time.Minute})
+	defer cache.Close()
+
+	cache.Set("small", 1)
+	cache.Set("large", strings.Repeat("x", 3500))
+	cache.Set("other", strings.Repeat("y", 3500)) // Does not fit next to "large"
+
+	if cache.Exists("large") {
+		t.Error("expected large to be evicted")
+	}
+	stats := cache.Stats()
+	if stats.Bytes <= 3500 || stats.Bytes > 4096 {
+		t.Errorf("expected usage between 3500 and 4096 bytes, got %d", stats.Bytes)
+	}
+
+	cache.DeleteAll()
+	if got := cache.Stats().Bytes; got != 0 {
+		t.Errorf("expected 0 bytes after DeleteAll, got %d", got)
+	}
+}
+
+func TestStatsWithoutByteBudget(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+	defer cache.Close()
+
+	cache.Set("a", strings.Repeat("a", 1000))
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Bytes != 0 || stats.MaxBytes != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,188 @@
+package in_memory_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(10, time.Minute)
+	defer cache.Close()
+
+	cache.Set("number", 1.5)
+	cache.Set("object", map[string]interface{}{"name": "alice", "tags": []interface{}{"a", "b"}})
+	cache.SetWithTTL("forever", "value", in_memory.NoExpiration)
+	cache.SetWithTTL("short", "value", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored := in_memory.NewInMemoryCache(10, time.Minute)
+	defer restored.Close()
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value, _ := restored.Get("number"); value != 1.5 {
+		t.Errorf("expected 1.5, got %v", value)
+	}
+	object, _ := restored.Get("object")
+	if m, ok := object.(map[string]interface{}); !ok || m["name"] != "alice" || len(m["tags"].([]interface{})) != 2 {
+		t.Errorf("expected the object back, got %#v", object)
+	}
+	if ttl, _ := restored.TTL("forever"); ttl != in_memory.NoExpiration {
+		t.Errorf("expected no expiry, got %s", ttl)
+	}
+	if ttl, _ := restored.TTL("number"); ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("expected the remaining TTL to be kept, got %s", ttl)
+	}
+	// Negative Test Case: expired entries are not saved
+	if restored.Exists("short") {
+		t.Error("expected the expired entry to be left out")
+	}
+}
+
+func TestSnapshotKeepsLRUOrder(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](3, time.Minute)
+	defer cache.Close()
+
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Set("key3", 3)
+	cache.Get("key1") // key2 is now least recently used
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored := in_memory.NewTyped[string, int](3, time.Minute)
+	defer restored.Close()
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored.Set("key4", 4)
+	if restored.Exists("key2") || !restored.Exists("key1") || !restored.Exists("key3") {
+		t.Errorf("expected key2 evicted first, got %v", restored.GetAllKeys())
+	}
+}
+
+func TestSnapshotIntoSmallerCache(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](3, time.Minute)
+	defer cache.Close()
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Set("key3", 3)
+
+	var buf bytes.Buffer
+	cache.SaveSnapshot(&buf)
+	restored := in_memory.NewTyped[string, int](2, time.Minute)
+	defer restored.Close()
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.Exists("key1") || !restored.Exists("key2") || !restored.Exists("key3") {
+		t.Errorf("expected the most recent entries kept, got %v", restored.GetAllKeys())
+	}
+}
+
+func TestLoadSnapshotInvalid(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(10, time.Minute)
+	defer cache.Close()
+
+	// Negative Test Cases: garbage and a truncated snapshot store nothing
+	if err := cache.LoadSnapshot(bytes.NewBufferString("not a snapshot")); err == nil {
+		t.Error("expected an error for garbage")
+	}
+
+	source := in_memory.NewInMemoryCache(10, time.Minute)
+	defer source.Close()
+	source.Set("key1", "value1")
+	source.Set("key2", "value2")
+	var buf bytes.Buffer
+	source.SaveSnapshot(&buf)
+	if err := cache.LoadSnapshot(bytes.NewReader(buf.Bytes()[:buf.Len()-4])); err == nil {
+		t.Error("expected an error for a truncated snapshot")
+	}
+	if keys := cache.GetAllKeys(); len(keys) != 0 {
+		t.Errorf("expected nothing stored, got %v", keys)
+	}
+
+	if err := cache.LoadSnapshotFile(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestSnapshotFileWarmRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	cfg := in_memory.Config[string, int]{MaxSize: 10, TTL: time.Minute, SnapshotPath: path}
+
+	// The first start finds no snapshot; Close saves one.
+	cache := in_memory.NewWithConfig(cfg)
+	cache.Set("key1", 1)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restarted := in_memory.NewWithConfig(cfg)
+	defer restarted.Close()
+	if stats := restarted.Stats(); stats.Sets != 0 {
+		t.Errorf("expected the restored entries not to be counted, got %d sets", stats.Sets)
+	}
+	if value, err := restarted.Get("key1"); err != nil || value != 1 {
+		t.Errorf("expected key1 restored, got %d, %v", value, err)
+	}
+}
+
+func TestSnapshotInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		MaxSize:          10,
+		TTL:              time.Minute,
+		SnapshotPath:     path,
+		SnapshotInterval: 10 * time.Millisecond,
+	})
+	defer cache.Close()
+	cache.Set("key1", 1)
+
+	reader := in_memory.NewTyped[string, int](10, time.Minute)
+	defer reader.Close()
+	deadline := time.Now().Add(time.Second)
+	for !reader.Exists("key1") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a periodic snapshot with key1")
+		}
+		time.Sleep(10 * time.Millisecond)
+		reader.LoadSnapshotFile(path)
+	}
+}
+
+func TestSnapshotLoadErrorReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	writer := in_memory.NewTyped[string, string](10, time.Minute)
+	writer.Set("key1", "value1")
+	writer.SaveSnapshotFile(path)
+	writer.Close()
+
+	// Negative Test Case: a snapshot of another value type
+	var reported error
+	cache := in_memory.NewWithConfig(in_memory.Config[string, int]{
+		MaxSize:         10,
+		TTL:             time.Minute,
+		SnapshotPath:    path,
+		OnSnapshotError: func(err error) { reported = err },
+	})
+	defer cache.Close()
+	if reported == nil {
+		t.Error("expected the load error to be reported")
+	}
+}
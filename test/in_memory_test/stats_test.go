@@ -0,0 +1,84 @@
+package in_memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestStatsCounters(t *testing.T) {
+	cache := in_memory.NewTyped[string, int](2, 5*time.Minute)
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("missing")
+	cache.Set("c", 3) // Evicts "b"
+	cache.Delete("a")
+	cache.Delete("missing")
+	cache.SetWithTTL("d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.Get("d") // Expired
+	cache.DeleteAll()
+
+	want := cachepkg.Stats{
+		Hits:    2,
+		Misses:  2,
+		Sets:    4,
+		Deletes: 2,
+		Evictions: cachepkg.EvictionStats{
+			Capacity: 1,
+			Expired:  1,
+			Deleted:  1,
+			Cleared:  1,
+		},
+	}
+	if got := cache.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if ratio := cache.Stats().HitRatio(); ratio != 0.5 {
+		t.Errorf("expected hit ratio 0.5, got %v", ratio)
+	}
+}
+
+func TestShardedStatsTotals(t *testing.T) {
+	cache := in_memory.NewSharded(in_memory.ShardedConfig[string, int]{Config: in_memory.Config[string, int]{TTL: 5 * time.Minute}, Shards: 4})
+	defer cache.Close()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		cache.Set(key, 1)
+		cache.Get(key)
+	}
+	stats := cache.Stats()
+	if stats.Sets != 5 || stats.Hits != 5 || stats.Entries != 5 {
+		t.Errorf("expected 5 sets, hits and entries, got %+v", stats)
+	}
+}
+
+func TestBackendStats(t *testing.T) {
+	backend := in_memory.NewBackend(in_memory.NewInMemoryCache(3, 5*time.Minute))
+	defer backend.Close()
+	ctx := context.Background()
+
+	backend.Set(ctx, "a", 1, 0)
+	backend.Get(ctx, "a")
+	stats, err := backend.Stats(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Hits != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// Negative Test Case: a cancelled context
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := backend.Stats(cancelled); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
@@ -0,0 +1,89 @@
+package in_memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+func TestSetWithTTL(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+
+	cache.SetWithTTL("short", 1, 50*time.Millisecond)
+	cache.Set("default", 2)
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := cache.Get("short"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected short-lived key to expire, got %v", err)
+	}
+	if _, err := cache.Get("default"); err != nil {
+		t.Errorf("expected key with default TTL to survive, got %v", err)
+	}
+}
+
+func TestSetWithNoExpiration(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 100*time.Millisecond)
+
+	cache.SetWithTTL("forever", 1, in_memory.NoExpiration)
+	time.Sleep(250 * time.Millisecond) // Long enough for the cleanup to run
+
+	if _, err := cache.Get("forever"); err != nil {
+		t.Errorf("expected key without expiry to survive, got %v", err)
+	}
+
+	ttl, err := cache.TTL("forever")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl != in_memory.NoExpiration {
+		t.Errorf("expected NoExpiration, got %v", ttl)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+
+	cache.SetWithTTL("key1", 1, time.Minute)
+	ttl, err := cache.TTL("key1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected TTL within (0, 1m], got %v", ttl)
+	}
+
+	// Negative Test Case: TTL of a missing key
+	if _, err := cache.TTL("nonexistent"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+}
+
+func TestExpireAndPersist(t *testing.T) {
+	cache := in_memory.NewInMemoryCache(3, 5*time.Minute)
+
+	cache.Set("key1", 1)
+	if err := cache.Expire("key1", 50*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Set("key2", 2)
+	if err := cache.Expire("key2", 50*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cache.Persist("key2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := cache.Get("key1"); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected key1 to expire, got %v", err)
+	}
+	if _, err := cache.Get("key2"); err != nil {
+		t.Errorf("expected persisted key2 to survive, got %v", err)
+	}
+
+	// Negative Test Case: expiring a missing key
+	if err := cache.Expire("nonexistent", time.Minute); err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package in_memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestTypedSetGet(t *testing.T) {
+	cache := in_memory.NewTyped[string, user](3, 5*time.Minute)
+
+	err := cache.Set("alice", user{Name: "Alice", Age: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// No type assertion needed: Get returns a user.
+	value, err := cache.Get("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value.Name != "Alice" || value.Age != 30 {
+		t.Errorf("expected Alice/30, got %+v", value)
+	}
+
+	// Negative Test Case: a miss returns the zero value
+	value, err = cache.Get("bob")
+	if err != in_memory.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+	if value != (user{}) {
+		t.Errorf("expected zero value on miss, got %+v", value)
+	}
+}
+
+func TestTypedIntKeys(t *testing.T) {
+	cache := in_memory.NewTyped[int, string](2, 5*time.Minute)
+
+	cache.Set(1, "one")
+	cache.Set(2, "two")
+	cache.Set(3, "three") // This should evict 1
+
+	if cache.Exists(1) {
+		t.Error("expected key 1 to be evicted")
+	}
+	keys := cache.GetAllKeys()
+	if len(keys) != 2 {
+		t.Errorf("expected 2 keys, got %v", keys)
+	}
+
+	// Negative Test Case: the zero key is rejected like an empty string
+	if err := cache.Set(0, "zero"); err == nil {
+		t.Error("expected error for zero key, got nil")
+	}
+}
@@ -0,0 +1,88 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/metrics"
+)
+
+func scrape(t *testing.T, c *metrics.Collector) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.WriteTo(context.Background(), &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	c := metrics.New("test", nil)
+	c.ObserveOperation("get", 50*time.Microsecond, nil)
+	c.ObserveOperation("get", 2*time.Millisecond, nil)
+	c.ObserveOperation("get", 10*time.Second, nil)
+
+	body := scrape(t, c)
+	for _, want := range []string{
+		`cache_operation_duration_seconds_bucket{backend="test",operation="get",le="0.0001"} 1`,
+		`cache_operation_duration_seconds_bucket{backend="test",operation="get",le="0.001"} 1`,
+		`cache_operation_duration_seconds_bucket{backend="test",operation="get",le="0.0025"} 2`,
+		`cache_operation_duration_seconds_bucket{backend="test",operation="get",le="2.5"} 2`,
+		`cache_operation_duration_seconds_bucket{backend="test",operation="get",le="+Inf"} 3`,
+		`cache_operation_duration_seconds_count{backend="test",operation="get"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in\n%s", want, body)
+		}
+	}
+}
+
+func TestErrorsExcludeMisses(t *testing.T) {
+	c := metrics.New("test", nil)
+	c.ObserveOperation("get", time.Millisecond, cache.ErrCacheMiss)
+	c.ObserveOperation("set", time.Millisecond, errors.New("connection refused"))
+
+	body := scrape(t, c)
+	if !strings.Contains(body, `cache_operation_errors_total{backend="test",operation="set"} 1`) {
+		t.Errorf("expected one set error in\n%s", body)
+	}
+	if strings.Contains(body, `cache_operation_errors_total{backend="test",operation="get"}`) {
+		t.Errorf("expected misses not to count as errors in\n%s", body)
+	}
+}
+
+func TestLabelValuesAreEscaped(t *testing.T) {
+	c := metrics.New(`a"b\c`, nil)
+	c.ObserveRequest("GET", "/x\ny", 200, time.Millisecond)
+
+	body := scrape(t, c)
+	want := `cache_http_requests_total{backend="a\"b\\c",method="GET",route="/x\ny",code="200"} 1`
+	if !strings.Contains(body, want) {
+		t.Errorf("expected %s in\n%s", want, body)
+	}
+}
+
+// failingStats is a StatsReporter whose backend is unreachable.
+type failingStats struct{}
+
+func (failingStats) Stats(ctx context.Context) (cache.Stats, error) {
+	return cache.Stats{}, errors.New("connection refused")
+}
+
+func TestStatsErrorStillWritesMetrics(t *testing.T) {
+	c := metrics.New("test", failingStats{})
+	c.RequestStarted()
+
+	var buf bytes.Buffer
+	if err := c.WriteTo(context.Background(), &buf); err == nil {
+		t.Errorf("expected an error from the stats reporter")
+	}
+	if !strings.Contains(buf.String(), `cache_http_requests_in_flight{backend="test"} 1`) {
+		t.Errorf("expected in-flight gauge in\n%s", buf.String())
+	}
+}
@@ -0,0 +1,348 @@
+package multicache_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/in_memory"
+	"github.com/Devisree146/Go_project-library.git/multicache"
+)
+
+func newMultiCache(opts ...multicache.Option) (*multicache.MultiCache, *in_memory.InMemoryCache, *in_memory.InMemoryCache) {
+	l1 := in_memory.NewInMemoryCache(3, 5*time.Minute)
+	l2 := in_memory.NewInMemoryCache(10, 5*time.Minute)
+	return multicache.New(in_memory.NewBackend(l1), in_memory.NewBackend(l2), opts...), l1, l2
+}
+
+func TestMultiCacheSetWritesBothLevels(t *testing.T) {
+	c, l1, l2 := newMultiCache()
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key1", 100, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !l1.Exists("key1") || !l2.Exists("key1") {
+		t.Error("expected key1 in both levels")
+	}
+}
+
+func TestMultiCacheGetFallsBackToL2(t *testing.T) {
+	c, _, l2 := newMultiCache()
+	ctx := context.Background()
+
+	l2.Set("key1", 100)
+	value, err := c.Get(ctx, "key1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != 100 {
+		t.Errorf("expected value 100, got %v", value)
+	}
+
+	// Negative Test Case: missing from both levels
+	_, err = c.Get(ctx, "nonexistent")
+	if err != cachepkg.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+}
+
+func TestMultiCacheBackfillsL1(t *testing.T) {
+	c, l1, l2 := newMultiCache()
+	ctx := context.Background()
+
+	l2.SetWithTTL("key1", 100, time.Minute)
+	if _, err := c.Get(ctx, "key1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !l1.Exists("key1") {
+		t.Fatal("expected key1 to be copied into L1")
+	}
+	// The L1 copy must not outlive the L2 entry.
+	ttl, err := l1.TTL("key1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected L1 TTL within (0, 1m], got %v", ttl)
+	}
+}
+
+func TestMultiCacheWriteAround(t *testing.T) {
+	c, l1, l2 := newMultiCache(multicache.WithWritePolicy(multicache.WriteAround))
+	ctx := context.Background()
+
+	l1.Set("key1", 1) // A stale copy
+	if err := c.Set(ctx, "key1", 2, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l1.Exists("key1") {
+		t.Error("expected the stale L1 copy to be dropped")
+	}
+	if value, _ := l2.Get("key1"); value != 2 {
+		t.Errorf("expected L2 value 2, got %v", value)
+	}
+
+	value, err := c.Get(ctx, "key1")
+	if err != nil || value != 2 {
+		t.Errorf("expected 2, nil; got %v, %v", value, err)
+	}
+}
+
+func TestMultiCacheWriteBack(t *testing.T) {
+	c, l1, l2 := newMultiCache(
+		multicache.WithWritePolicy(multicache.WriteBack),
+		multicache.WithFlushInterval(time.Hour),
+	)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key1", 100, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !l1.Exists("key1") {
+		t.Error("expected key1 in L1 immediately")
+	}
+	if l2.Exists("key1") {
+		t.Error("expected key1 not to reach L2 before a flush")
+	}
+
+	// A queued write is still readable after L1 loses it.
+	l1.Delete("key1")
+	if value, err := c.Get(ctx, "key1"); err != nil || value != 100 {
+		t.Errorf("expected 100, nil; got %v, %v", value, err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !l2.Exists("key1") {
+		t.Error("expected Close to flush key1 to L2")
+	}
+}
+
+func TestMultiCacheWriteBackNonPositiveFlushInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		c, _, l2 := newMultiCache(
+			multicache.WithWritePolicy(multicache.WriteBack),
+			multicache.WithFlushInterval(interval),
+		)
+		ctx := context.Background()
+
+		c.Set(ctx, "key1", 100, 0)
+		if err := c.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !l2.Exists("key1") {
+			t.Errorf("expected Close to flush key1 to L2 with flush interval %v", interval)
+		}
+	}
+}
+
+func TestMultiCacheDeleteDropsQueuedWrite(t *testing.T) {
+	c, _, l2 := newMultiCache(
+		multicache.WithWritePolicy(multicache.WriteBack),
+		multicache.WithFlushInterval(time.Hour),
+	)
+	ctx := context.Background()
+
+	c.Set(ctx, "key1", 100, 0)
+	if err := c.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Flush(ctx)
+	if l2.Exists("key1") {
+		t.Error("expected a deleted key not to be flushed to L2")
+	}
+}
+
+func TestMultiCacheDelete(t *testing.T) {
+	c, l1, l2 := newMultiCache()
+	ctx := context.Background()
+
+	// A key held only by L2 can still be deleted.
+	l2.Set("key1", 100)
+	if err := c.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l1.Exists("key1") || l2.Exists("key1") {
+		t.Error("expected key1 to be deleted from both levels")
+	}
+
+	// Negative Test Case: missing from both levels
+	if err := c.Delete(ctx, "key1"); err != cachepkg.ErrCacheMiss {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+}
+
+func TestMultiCacheKeysAreDeduplicated(t *testing.T) {
+	c, _, l2 := newMultiCache()
+	ctx := context.Background()
+
+	c.Set(ctx, "key1", 1, 0)
+	l2.Set("key2", 2)
+
+	keys, err := c.Keys(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected 2 keys, got %v", keys)
+	}
+}
+
+func TestMultiCacheCloseClosesBothLevels(t *testing.T) {
+	before := runtime.NumGoroutine()
+	c, _, _ := newMultiCache(multicache.WithWritePolicy(multicache.WriteBack))
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("unexpected error on second Close: %v", err)
+	}
+	// The flusher and both cleanup goroutines are gone.
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected background goroutines to stop, %d before and %d after", before, after)
+	}
+}
+
+func TestMultiCacheL2EvictionInvalidatesL1(t *testing.T) {
+	l1 := in_memory.NewInMemoryCache(10, 5*time.Minute)
+	l2 := in_memory.NewInMemoryCache(2, 5*time.Minute)
+	c := multicache.New(in_memory.NewBackend(l1), in_memory.NewBackend(l2))
+	defer c.Close()
+	ctx := context.Background()
+
+	c.Set(ctx, "key1", 1, 0)
+	c.Set(ctx, "key2", 2, 0)
+	c.Set(ctx, "key3", 3, 0) // L2 evicts key1
+
+	if l1.Exists("key1") {
+		t.Error("expected key1 to be dropped from L1 when L2 evicted it")
+	}
+	if !l1.Exists("key2") || !l1.Exists("key3") {
+		t.Error("expected key2 and key3 to remain in L1")
+	}
+}
+
+func TestMultiCacheL2EvictionKeepsPendingWrites(t *testing.T) {
+	l1 := in_memory.NewInMemoryCache(10, 5*time.Minute)
+	l2 := in_memory.NewInMemoryCache(1, 5*time.Minute)
+	c := multicache.New(in_memory.NewBackend(l1), in_memory.NewBackend(l2), multicache.WithWritePolicy(multicache.WriteBack), multicache.WithFlushInterval(time.Hour))
+	defer c.Close()
+	ctx := context.Background()
+
+	l2.Set("key1", 1)
+	c.Set(ctx, "key1", 10, 0) // Queued for L2
+	l2.Set("key2", 2)         // L2 evicts its stale key1
+
+	value, err := c.Get(ctx, "key1")
+	if err != nil || value != 10 {
+		t.Errorf("expected the queued value 10, got %v, %v", value, err)
+	}
+}
+
+func TestMultiCacheStatsSplitsHits(t *testing.T) {
+	c, _, l2 := newMultiCache()
+	defer c.Close()
+	ctx := context.Background()
+
+	c.Set(ctx, "key1", 1, 0)
+	l2.Set("key2", 2)
+	c.Get(ctx, "key1")    // L1 hit
+	c.Get(ctx, "key2")    // L2 hit, backfills L1
+	c.Get(ctx, "key2")    // L1 hit
+	c.Get(ctx, "missing") // Miss
+
+	stats, err := c.Stats(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.L1Hits != 2 || stats.L2Hits != 1 || stats.Hits != 3 || stats.Misses != 1 || stats.Sets != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.L1 == nil || stats.L2 == nil {
+		t.Fatal("expected stats for both levels")
+	}
+	if stats.L1.Entries != 2 || stats.L2.Entries != 2 {
+		t.Errorf("expected 2 entries per level, got %d and %d", stats.L1.Entries, stats.L2.Entries)
+	}
+}
+
+func TestMultiCacheGetOrLoad(t *testing.T) {
+	c, l1, l2 := newMultiCache(multicache.WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	var calls int
+	loader := func() (interface{}, error) {
+		calls++
+		return 100, nil
+	}
+	for i := 0; i < 2; i++ {
+		value, err := c.GetOrLoad(ctx, "key1", loader)
+		if err != nil || value != 100 {
+			t.Fatalf("expected 100, got %v, %v", value, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one loader call, got %d", calls)
+	}
+	if !l1.Exists("key1") || !l2.Exists("key1") {
+		t.Error("expected the loaded value in both levels")
+	}
+
+	// A value already in L2 is returned without loading
+	l2.Set("key2", 200)
+	if value, err := c.GetOrLoad(ctx, "key2", loader); err != nil || value != 200 || calls != 1 {
+		t.Errorf("expected 200 from L2 without loading, got %v, %v after %d calls", value, err, calls)
+	}
+
+	// Negative results are remembered
+	notFound := func() (interface{}, error) {
+		calls++
+		return nil, cachepkg.ErrCacheMiss
+	}
+	c.GetOrLoad(ctx, "absent", notFound)
+	if _, err := c.GetOrLoad(ctx, "absent", notFound); err != cachepkg.ErrCacheMiss || calls != 2 {
+		t.Errorf("expected a remembered miss, got %v after %d calls", err, calls)
+	}
+}
+
+func TestMultiCacheBatchOperations(t *testing.T) {
+	c, l1, l2 := newMultiCache()
+	ctx := context.Background()
+
+	if err := c.SetMulti(ctx, map[string]interface{}{"key1": 1, "key2": 2}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !l1.Exists("key1") || !l2.Exists("key2") {
+		t.Error("expected the batch in both levels")
+	}
+
+	l2.Set("key3", 3) // Only in L2
+	values, err := c.GetMulti(ctx, []string{"key1", "key3", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 2 || values["key1"] != 1 || values["key3"] != 3 {
+		t.Errorf("expected key1 and key3, got %v", values)
+	}
+	if !l1.Exists("key3") {
+		t.Error("expected key3 backfilled into L1")
+	}
+	stats, _ := c.Stats(ctx)
+	if stats.L1Hits != 1 || stats.L2Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 L1 hit, 1 L2 hit and 1 miss, got %+v", stats)
+	}
+
+	deleted, err := c.DeleteMulti(ctx, []string{"key1", "key3", "missing"})
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("expected key1 and key3 deleted, got %v, %v", deleted, err)
+	}
+	if l1.Exists("key1") || l2.Exists("key3") {
+		t.Error("expected deleted keys gone from both levels")
+	}
+}
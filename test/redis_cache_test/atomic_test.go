@@ -0,0 +1,110 @@
+package redis_cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+func newAtomicCache(t *testing.T, maxSize int) *redis_cache.Cache {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, maxSize, redis_cache.WithPrefix("atomic-test:"))
+	cache.DeleteAll()
+	t.Cleanup(func() {
+		cache.DeleteAll()
+		cache.Close()
+	})
+	return cache
+}
+
+func TestRedisCache_IncrBy(t *testing.T) {
+	cache := newAtomicCache(t, 10)
+
+	// A missing key counts as zero
+	if n, err := cache.IncrBy("counter", 5); err != nil || n != 5 {
+		t.Fatalf("expected 5, got %d, %v", n, err)
+	}
+	if n, err := cache.Decr("counter"); err != nil || n != 4 {
+		t.Errorf("expected 4, got %d, %v", n, err)
+	}
+
+	// Integers stored by JSONCodec can be incremented and read back
+	cache.SetValue("json", 41, redis_cache.StandardTTL)
+	if n, err := cache.Incr("json"); err != nil || n != 42 {
+		t.Errorf("expected 42, got %d, %v", n, err)
+	}
+	if value, err := redis_cache.GetAs[int](cache, "json"); err != nil || value != 42 {
+		t.Errorf("expected 42, got %d, %v", value, err)
+	}
+	if keys, _ := cache.GetAllKeys(); len(keys) != 2 {
+		t.Errorf("expected the counters in the LRU index, got %v", keys)
+	}
+
+	// Negative Test Case: a value that is not an integer
+	cache.SetValue("text", "hello", redis_cache.StandardTTL)
+	if _, err := cache.Incr("text"); !errors.Is(err, cachepkg.ErrNotInteger) {
+		t.Errorf("expected ErrNotInteger, got %v", err)
+	}
+}
+
+func TestRedisCache_SetNXAndReplace(t *testing.T) {
+	cache := newAtomicCache(t, 10)
+
+	if ok, err := cache.Replace("key1", "value1", redis_cache.StandardTTL); err != nil || ok {
+		t.Errorf("expected Replace of a missing key to do nothing, got %v, %v", ok, err)
+	}
+	if ok, err := cache.SetNX("key1", "value1", redis_cache.StandardTTL); err != nil || !ok {
+		t.Errorf("expected SetNX to store, got %v, %v", ok, err)
+	}
+	if ok, _ := cache.SetNX("key1", "value2", redis_cache.StandardTTL); ok {
+		t.Error("expected SetNX of an existing key to do nothing")
+	}
+	if value, _ := redis_cache.GetAs[string](cache, "key1"); value != "value1" {
+		t.Errorf("expected value1, got %q", value)
+	}
+	if ok, err := cache.Replace("key1", "value3", redis_cache.StandardTTL); err != nil || !ok {
+		t.Errorf("expected Replace to store, got %v, %v", ok, err)
+	}
+	if value, _ := redis_cache.GetAs[string](cache, "key1"); value != "value3" {
+		t.Errorf("expected value3, got %q", value)
+	}
+}
+
+func TestRedisCache_CompareAndSwap(t *testing.T) {
+	cache := newAtomicCache(t, 10)
+
+	cache.SetValue("key1", 1, time.Minute)
+	var value int
+	version, err := cache.GetValueVersioned("key1", &value)
+	if err != nil || value != 1 || version == 0 {
+		t.Fatalf("expected 1 with a version, got %d, %d, %v", value, version, err)
+	}
+
+	next, err := cache.CompareAndSwap("key1", 2, version, time.Minute)
+	if err != nil || next == version {
+		t.Fatalf("expected a new version, got %d, %v", next, err)
+	}
+
+	// Negative Test Case: the old version no longer matches
+	if _, err := cache.CompareAndSwap("key1", 3, version, time.Minute); !errors.Is(err, cachepkg.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+	if got, _ := redis_cache.GetAs[int](cache, "key1"); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
+
+	// Negative Test Case: the key is gone
+	cache.Delete("key1")
+	if _, err := cache.CompareAndSwap("key1", 3, next, time.Minute); !errors.Is(err, cachepkg.ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	// Versions are not reused after the cache is cleared
+	cache.DeleteAll()
+	cache.SetValue("key1", 4, time.Minute)
+	if again, _ := cache.GetValueVersioned("key1", &value); again == version || again == next {
+		t.Errorf("expected a fresh version, got %d again", again)
+	}
+}
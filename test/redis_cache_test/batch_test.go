@@ -0,0 +1,94 @@
+package redis_cache_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/go-redis/redis/v8"
+)
+
+func newBatchCache(t *testing.T, maxSize int) *redis_cache.Cache {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, maxSize, redis_cache.WithPrefix("batch-test:"))
+	cache.DeleteAll()
+	t.Cleanup(func() {
+		cache.DeleteAll()
+		cache.Close()
+	})
+	return cache
+}
+
+func TestRedisCache_MultiOperations(t *testing.T) {
+	cache := newBatchCache(t, 10)
+
+	err := cache.SetMulti(map[string]interface{}{"key1": 1, "key2": 2, "key3": 3}, redis_cache.StandardTTL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values, err := redis_cache.GetMultiAs[int](cache, []string{"key1", "key3", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 2 || values["key1"] != 1 || values["key3"] != 3 {
+		t.Errorf("expected key1 and key3, got %v", values)
+	}
+
+	deleted, err := cache.DeleteMulti([]string{"key1", "key2", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Strings(deleted)
+	if len(deleted) != 2 || deleted[0] != "key1" || deleted[1] != "key2" {
+		t.Errorf("expected key1 and key2 deleted, got %v", deleted)
+	}
+
+	stats, _ := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Sets != 3 || stats.Entries != 1 {
+		t.Errorf("expected 2 hits, 1 miss, 3 sets and 1 entry, got %+v", stats)
+	}
+}
+
+func TestRedisCache_MultiKeepsLRUOrder(t *testing.T) {
+	cache := newBatchCache(t, 3)
+
+	cache.SetMulti(map[string]interface{}{"key1": 1, "key2": 2, "key3": 3}, redis_cache.StandardTTL)
+	// Reading key1 and key2 leaves key3 least recently used
+	redis_cache.GetMultiAs[int](cache, []string{"key1", "key2"})
+	cache.Set("key4", 4, redis_cache.StandardTTL)
+
+	if ok, _ := cache.Exists("key3"); ok {
+		t.Error("expected key3 to be evicted")
+	}
+	for _, key := range []string{"key1", "key2", "key4"} {
+		if ok, _ := cache.Exists(key); !ok {
+			t.Errorf("expected %s to survive", key)
+		}
+	}
+}
+
+func TestRedisCache_GetMultiReportsExpired(t *testing.T) {
+	cache := newBatchCache(t, 10)
+	var expired []string
+	cache.OnEvict(func(key string, data []byte, reason cachepkg.EvictionReason) {
+		if reason == cachepkg.ReasonExpired {
+			expired = append(expired, key)
+		}
+	})
+
+	cache.SetMulti(map[string]interface{}{"key1": 1, "key2": 2}, redis_cache.StandardTTL)
+	// Simulate Redis expiring key1 behind the cache's back.
+	raw := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer raw.Close()
+	raw.Del(context.Background(), "batch-test:key1")
+
+	values, err := redis_cache.GetMultiAs[int](cache, []string{"key1", "key2"})
+	if err != nil || len(values) != 1 {
+		t.Fatalf("expected only key2, got %v, %v", values, err)
+	}
+	if len(expired) != 1 || expired[0] != "key1" {
+		t.Errorf("expected key1 reported as expired, got %v", expired)
+	}
+}
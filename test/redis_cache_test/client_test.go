@@ -0,0 +1,123 @@
+package redis_cache_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+// tlsProxy terminates TLS with a self-signed certificate for 127.0.0.1 and
+// forwards the connections to the local Redis. It returns its address and
+// a pool trusting the certificate.
+func tlsProxy(t *testing.T) (string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				upstream, err := net.Dial("tcp", "localhost:6379")
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return listener.Addr().String(), pool
+}
+
+func TestRedisCache_NewWithOptionsTLS(t *testing.T) {
+	addr, pool := tlsProxy(t)
+	cache := redis_cache.NewWithOptions(redis_cache.ClientOptions{
+		Addr:         addr,
+		TLSConfig:    &tls.Config{RootCAs: pool},
+		PoolSize:     2,
+		DialTimeout:  time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		MaxRetries:   -1,
+	}, 10, redis_cache.WithPrefix("tls-test:"))
+	defer cache.Close()
+	defer cache.DeleteAll()
+
+	if err := cache.Set("key1", 1, redis_cache.StandardTTL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, err := cache.Get("key1"); err != nil || value != 1 {
+		t.Errorf("expected 1 over TLS, got %d, %v", value, err)
+	}
+}
+
+func TestRedisCache_NewWithOptionsUntrustedCertificate(t *testing.T) {
+	addr, _ := tlsProxy(t)
+
+	// Negative Test Case: the system pool does not trust the proxy
+	cache := redis_cache.NewWithOptions(redis_cache.ClientOptions{
+		Addr:        addr,
+		TLSConfig:   &tls.Config{},
+		DialTimeout: time.Second,
+		MaxRetries:  -1,
+	}, 10, redis_cache.WithPrefix("tls-test:"))
+	defer cache.Close()
+
+	if err := cache.Set("key1", 1, redis_cache.StandardTTL); err == nil {
+		t.Error("expected the handshake to fail")
+	}
+}
+
+func TestRedisCache_NewWithOptionsPlain(t *testing.T) {
+	cache := redis_cache.NewWithOptions(redis_cache.ClientOptions{Addr: "localhost:6379", PoolSize: 1}, 10,
+		redis_cache.WithPrefix("tls-test:"))
+	defer cache.Close()
+	defer cache.DeleteAll()
+
+	// A single connection still serves every call in turn.
+	for i := 0; i < 5; i++ {
+		if err := cache.Set("key1", i, redis_cache.StandardTTL); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if value, _ := cache.Get("key1"); value != 4 {
+		t.Errorf("expected 4, got %d", value)
+	}
+}
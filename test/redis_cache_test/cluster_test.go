@@ -0,0 +1,150 @@
+package redis_cache_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// exerciseShards checks that a sharded cache holding at most 8 keys reads,
+// evicts, lists and deletes across all of its shards.
+func exerciseShards(t *testing.T, cache *redis_cache.Cache) {
+	cache.DeleteAll()
+	t.Cleanup(func() {
+		cache.DeleteAll()
+		cache.Close()
+	})
+	var evicted []string
+	cache.OnEvict(func(key string, data []byte, reason cachepkg.EvictionReason) {
+		if reason == cachepkg.ReasonCapacity {
+			evicted = append(evicted, key)
+		}
+	})
+
+	for i := 0; i < 40; i++ {
+		if err := cache.Set(fmt.Sprintf("key%d", i), i, redis_cache.StandardTTL); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if value, err := cache.Get("key39"); err != nil || value != 39 {
+		t.Errorf("expected the last key kept, got %d, %v", value, err)
+	}
+
+	keys, err := cache.GetAllKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) == 0 || len(keys) > 8 || len(keys)+len(evicted) != 40 {
+		t.Errorf("expected at most 8 keys and the rest evicted, got %v and %d evicted", keys, len(evicted))
+	}
+	for _, key := range append(keys, evicted...) {
+		if !strings.HasPrefix(key, "key") {
+			t.Errorf("expected keys without their shard tag, got %q", key)
+		}
+	}
+	if stats, _ := cache.Stats(); stats.Entries != len(keys) {
+		t.Errorf("expected %d entries across the shards, got %d", len(keys), stats.Entries)
+	}
+
+	values, err := redis_cache.GetMultiAs[int](cache, keys)
+	if err != nil || len(values) != len(keys) {
+		t.Errorf("expected every listed key from GetMulti, got %v, %v", values, err)
+	}
+	deleted, err := cache.DeleteMulti(keys[:len(keys)/2])
+	if err != nil || len(deleted) != len(keys)/2 {
+		t.Errorf("expected %d keys deleted, got %v, %v", len(keys)/2, deleted, err)
+	}
+
+	if err := cache.DeleteAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys, _ := cache.GetAllKeys(); len(keys) != 0 {
+		t.Errorf("expected no keys after DeleteAll, got %v", keys)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected empty LRU indexes after DeleteAll, got %d", stats.Entries)
+	}
+}
+
+func TestRedisCache_Shards(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 8,
+		redis_cache.WithPrefix("shard-test:"), redis_cache.WithShards(4))
+	exerciseShards(t, cache)
+}
+
+func TestRedisCache_ShardedKeysAreTagged(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 0,
+		redis_cache.WithPrefix("shard-test:"), redis_cache.WithShards(4))
+	defer cache.Close()
+	defer cache.DeleteAll()
+	cache.SetMulti(map[string]interface{}{"key1": 1, "key2": 2, "key3": 3, "key4": 4}, redis_cache.StandardTTL)
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	stored, err := client.Keys(context.Background(), "shard-test:*").Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Strings(stored)
+	if len(stored) != 4 {
+		t.Fatalf("expected 4 stored keys, got %v", stored)
+	}
+	for _, key := range stored {
+		var shard int
+		var name string
+		if _, err := fmt.Sscanf(key, "shard-test:{%d}%s", &shard, &name); err != nil || shard < 0 || shard >= 4 {
+			t.Errorf("expected a key tagged with its shard, got %q", key)
+		}
+	}
+}
+
+func TestRedisCache_NewWithClient(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	cache := redis_cache.NewWithClient(client, 2, redis_cache.WithPrefix("client-test:"))
+	defer cache.Close()
+	defer cache.DeleteAll()
+
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+	cache.Set("key3", 3, redis_cache.StandardTTL)
+	if keys, _ := cache.GetAllKeys(); len(keys) != 2 {
+		t.Errorf("expected a single shard holding 2 keys, got %v", keys)
+	}
+	// An unsharded cache keeps the plain key layout.
+	if n, _ := client.Exists(context.Background(), "client-test:key3").Result(); n != 1 {
+		t.Error("expected key3 stored without a shard tag")
+	}
+}
+
+// The tests below need real deployments and are skipped unless pointed at
+// one, e.g. with REDIS_CLUSTER_ADDRS=localhost:7000,localhost:7001,localhost:7002
+// for a cluster of local redis-server processes.
+
+func TestRedisCache_Cluster(t *testing.T) {
+	addrs := os.Getenv("REDIS_CLUSTER_ADDRS")
+	if addrs == "" {
+		t.Skip("REDIS_CLUSTER_ADDRS not set")
+	}
+	cache := redis_cache.NewClusterCache(&redis.ClusterOptions{Addrs: strings.Split(addrs, ",")}, 8,
+		redis_cache.WithPrefix("cluster-test:"), redis_cache.WithShards(4))
+	exerciseShards(t, cache)
+}
+
+func TestRedisCache_Failover(t *testing.T) {
+	addrs, master := os.Getenv("REDIS_SENTINEL_ADDRS"), os.Getenv("REDIS_SENTINEL_MASTER")
+	if addrs == "" || master == "" {
+		t.Skip("REDIS_SENTINEL_ADDRS and REDIS_SENTINEL_MASTER not set")
+	}
+	cache := redis_cache.NewFailoverCache(&redis.FailoverOptions{
+		MasterName:    master,
+		SentinelAddrs: strings.Split(addrs, ","),
+	}, 8, redis_cache.WithPrefix("failover-test:"), redis_cache.WithShards(4))
+	exerciseShards(t, cache)
+}
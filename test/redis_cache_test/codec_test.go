@@ -0,0 +1,221 @@
+package redis_cache_test
+
+import (
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+type profile struct {
+	Name string
+	Tags []string
+}
+
+// point is a fixed-size struct, which BinaryCodec can store.
+type point struct {
+	X, Y  int32
+	Scale float64
+	Shown bool
+}
+
+func init() {
+	gob.Register(profile{})
+	gob.Register(map[string]int{})
+	gob.Register([3]int32{})
+	gob.Register([4]byte{})
+	gob.Register([2][2]string{})
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	codecs := map[string]redis_cache.Codec{
+		"json":   redis_cache.JSONCodec,
+		"gob":    redis_cache.GobCodec,
+		"binary": redis_cache.BinaryCodec,
+	}
+	values := []interface{}{
+		42,
+		"hello",
+		[]string{"a", "b"},
+		map[string]int{"x": 1},
+	}
+
+	for name, codec := range codecs {
+		for _, value := range values {
+			data, err := codec.Marshal(value)
+			if err != nil {
+				t.Fatalf("%s Marshal(%v) error = %v", name, value, err)
+			}
+
+			got := reflect.New(reflect.TypeOf(value))
+			if err := codec.Unmarshal(data, got.Interface()); err != nil {
+				t.Fatalf("%s Unmarshal(%v) error = %v", name, value, err)
+			}
+			if !reflect.DeepEqual(got.Elem().Interface(), value) {
+				t.Errorf("%s round trip got = %v, want %v", name, got.Elem().Interface(), value)
+			}
+		}
+	}
+}
+
+func TestCodecs_Struct(t *testing.T) {
+	want := profile{Name: "alice", Tags: []string{"admin"}}
+
+	for name, codec := range map[string]redis_cache.Codec{"json": redis_cache.JSONCodec, "gob": redis_cache.GobCodec} {
+		data, err := codec.Marshal(want)
+		if err != nil {
+			t.Fatalf("%s Marshal() error = %v", name, err)
+		}
+		var got profile
+		if err := codec.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s Unmarshal() error = %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s round trip got = %v, want %v", name, got, want)
+		}
+	}
+
+	// Negative test case: the binary codec does not handle structs that are not fixed-size
+	if _, err := redis_cache.BinaryCodec.Marshal(want); !errors.Is(err, redis_cache.ErrUnsupportedType) {
+		t.Errorf("BinaryCodec.Marshal() error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestCodecs_BinaryFixedSizeStruct(t *testing.T) {
+	want := []point{{X: 1, Y: -2, Scale: 0.5, Shown: true}, {X: 3}}
+
+	data, err := redis_cache.BinaryCodec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var got []point
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip got = %v, want %v", got, want)
+	}
+
+	// Negative test case: a struct of another layout
+	var other []struct{ X int64 }
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &other); !errors.Is(err, redis_cache.ErrUnsupportedType) {
+		t.Errorf("Unmarshal() error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestCodecs_Arrays(t *testing.T) {
+	values := []interface{}{
+		[3]int32{1, -2, 3},
+		[4]byte{0, 1, 2, 255},
+		[2][2]string{{"a", "b"}, {"c", "d"}},
+	}
+
+	for name, codec := range map[string]redis_cache.Codec{"gob": redis_cache.GobCodec, "binary": redis_cache.BinaryCodec} {
+		for _, value := range values {
+			data, err := codec.Marshal(value)
+			if err != nil {
+				t.Fatalf("%s Marshal(%v) error = %v", name, value, err)
+			}
+			got := reflect.New(reflect.TypeOf(value))
+			if err := codec.Unmarshal(data, got.Interface()); err != nil {
+				t.Fatalf("%s Unmarshal(%v) error = %v", name, value, err)
+			}
+			if !reflect.DeepEqual(got.Elem().Interface(), value) {
+				t.Errorf("%s round trip got = %v, want %v", name, got.Elem().Interface(), value)
+			}
+		}
+	}
+
+	// Negative test case: an array of another length
+	data, _ := redis_cache.BinaryCodec.Marshal([3]int32{1, 2, 3})
+	var short [2]int32
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &short); err == nil {
+		t.Errorf("Unmarshal() into %T expected error, got nil", short)
+	}
+}
+
+func TestCodecs_NumberConversions(t *testing.T) {
+	data, _ := redis_cache.BinaryCodec.Marshal(int64(100))
+	var small int8
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &small); err != nil || small != 100 {
+		t.Errorf("Unmarshal() into int8 got = %v, %v, want 100, nil", small, err)
+	}
+	var f float32
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &f); err != nil || f != 100 {
+		t.Errorf("Unmarshal() into float32 got = %v, %v, want 100, nil", f, err)
+	}
+	data, _ = redis_cache.BinaryCodec.Marshal(2.0)
+	var whole int
+	if err := redis_cache.BinaryCodec.Unmarshal(data, &whole); err != nil || whole != 2 {
+		t.Errorf("Unmarshal() of 2.0 into int got = %v, %v, want 2, nil", whole, err)
+	}
+
+	// Negative test cases: values the destination cannot hold exactly
+	tests := []struct {
+		value interface{}
+		dst   interface{}
+	}{
+		{int64(300), new(int8)},
+		{int64(-1), new(uint)},
+		{uint64(1 << 63), new(int64)},
+		{1.5, new(int)},
+		{-2.0, new(uint8)},
+		{1e300, new(float32)},
+		{int64(1<<53 + 1), new(float64)},
+	}
+	for _, tt := range tests {
+		for name, codec := range map[string]redis_cache.Codec{"gob": redis_cache.GobCodec, "binary": redis_cache.BinaryCodec} {
+			data, err := codec.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("%s Marshal(%v) error = %v", name, tt.value, err)
+			}
+			if err := codec.Unmarshal(data, tt.dst); err == nil {
+				t.Errorf("%s Unmarshal(%v) into %T expected error, got nil", name, tt.value, tt.dst)
+			}
+		}
+	}
+}
+
+func TestCodecs_Raw(t *testing.T) {
+	data, err := redis_cache.RawCodec.Marshal([]byte{0, 1, 2})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var got []byte
+	if err := redis_cache.RawCodec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []byte{0, 1, 2}) {
+		t.Errorf("round trip got = %v, want [0 1 2]", got)
+	}
+
+	// Negative test case: raw codec only stores bytes and strings
+	if _, err := redis_cache.RawCodec.Marshal(42); err == nil {
+		t.Errorf("Marshal() expected error for int, got nil")
+	}
+}
+
+func TestRedisCache_SetValueGetAs(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3, redis_cache.WithCodec(redis_cache.GobCodec))
+	defer cache.Delete("profile_key")
+
+	want := profile{Name: "bob", Tags: []string{"dev", "ops"}}
+	if err := cache.SetValue("profile_key", want, redis_cache.StandardTTL); err != nil {
+		t.Fatalf("SetValue() error = %v, want nil", err)
+	}
+
+	got, err := redis_cache.GetAs[profile](cache, "profile_key")
+	if err != nil {
+		t.Fatalf("GetAs() error = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAs() got = %v, want %v", got, want)
+	}
+
+	// Negative test case: typed get of a missing key
+	if _, err := redis_cache.GetAs[profile](cache, "missing_profile_key"); err != redis_cache.ErrCacheMiss {
+		t.Errorf("GetAs() error = %v, want ErrCacheMiss", err)
+	}
+}
@@ -0,0 +1,68 @@
+package redis_cache_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+// silentServer accepts connections but never answers, like a hung Redis.
+func silentServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestRedisCache_OperationTimeout(t *testing.T) {
+	addr := silentServer(t)
+	cache := redis_cache.NewRedisCache(addr, "", 0, 3, redis_cache.WithTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	_, err := cache.GetCtx(context.Background(), "key1")
+	if !errors.Is(err, cachepkg.ErrTimeout) {
+		t.Errorf("GetCtx() error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetCtx() took %v, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestRedisCache_ContextDeadline(t *testing.T) {
+	addr := silentServer(t)
+	cache := redis_cache.NewRedisCache(addr, "", 0, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := cache.SetCtx(ctx, "key1", 1, redis_cache.StandardTTL)
+	if !errors.Is(err, cachepkg.ErrTimeout) {
+		t.Errorf("SetCtx() error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestRedisCache_ContextCanceled(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.GetAllKeysCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetAllKeysCtx() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,70 @@
+package redis_cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+func TestRedisCache_LRUEviction(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 2)
+	cache.DeleteAll()
+	defer cache.DeleteAll()
+
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+	cache.Get("key1")                             // key2 is now the least recently used
+	cache.Set("key3", 3, redis_cache.StandardTTL) // This should evict key2
+
+	if _, err := cache.Get("key2"); err != redis_cache.ErrCacheMiss {
+		t.Errorf("Get(key2) error = %v, want ErrCacheMiss", err)
+	}
+	for _, key := range []string{"key1", "key3"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("Get(%s) error = %v, want nil", key, err)
+		}
+	}
+
+	keys, err := cache.GetAllKeys()
+	if err != nil {
+		t.Fatalf("GetAllKeys() error = %v, want nil", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("GetAllKeys() got = %v, want 2 keys", keys)
+	}
+}
+
+func TestRedisCache_UpdateDoesNotEvict(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 2)
+	cache.DeleteAll()
+	defer cache.DeleteAll()
+
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+	cache.Set("key1", 10, redis_cache.StandardTTL) // Overwriting keeps the size at 2
+
+	for _, key := range []string{"key1", "key2"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("Get(%s) error = %v, want nil", key, err)
+		}
+	}
+}
+
+func TestRedisCache_ExpiredKeysDoNotTakeCapacity(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
+	cache.DeleteAll()
+	defer cache.DeleteAll()
+
+	cache.Set("live1", 1, redis_cache.StandardTTL)
+	cache.Set("dead", 0, 20*time.Millisecond)
+	cache.Set("live2", 2, redis_cache.StandardTTL)
+	time.Sleep(50 * time.Millisecond)              // Redis expires dead, but it stays in the LRU index
+	cache.Set("live3", 3, redis_cache.StandardTTL) // The dead key's slot is reclaimed instead of evicting live1
+
+	for _, key := range []string{"live1", "live2", "live3"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("Get(%s) error = %v, want nil", key, err)
+		}
+	}
+}
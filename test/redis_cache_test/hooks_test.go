@@ -0,0 +1,135 @@
+package redis_cache_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	cachepkg "github.com/Devisree146/Go_project-library.git/cache"
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/go-redis/redis/v8"
+)
+
+type redisEviction struct {
+	key    string
+	data   string
+	reason cachepkg.EvictionReason
+}
+
+func newHookedCache(t *testing.T, maxSize int) (*redis_cache.Cache, *[]redisEviction) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, maxSize, redis_cache.WithPrefix("hooks-test:"))
+	cache.DeleteAll()
+	t.Cleanup(func() {
+		cache.DeleteAll()
+		cache.Close()
+	})
+
+	events := &[]redisEviction{}
+	cache.OnEvict(func(key string, data []byte, reason cachepkg.EvictionReason) {
+		*events = append(*events, redisEviction{key, string(data), reason})
+	})
+	return cache, events
+}
+
+func TestRedisCache_OnEvictCapacityAndDelete(t *testing.T) {
+	cache, events := newHookedCache(t, 2)
+
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+	cache.Set("key3", 3, redis_cache.StandardTTL) // Evicts key1
+	cache.Delete("key2")
+	cache.Delete("missing") // Nothing to report
+
+	want := []redisEviction{
+		{"key1", "1", cachepkg.ReasonCapacity},
+		{"key2", "2", cachepkg.ReasonDeleted},
+	}
+	if len(*events) != len(want) {
+		t.Fatalf("OnEvict events = %v, want %v", *events, want)
+	}
+	for i := range want {
+		if (*events)[i] != want[i] {
+			t.Errorf("event %d = %v, want %v", i, (*events)[i], want[i])
+		}
+	}
+}
+
+func TestRedisCache_OnEvictExpired(t *testing.T) {
+	cache, events := newHookedCache(t, 10)
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+
+	// Remove the value behind the cache's back, as an expiry would.
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	client.Del(context.Background(), "hooks-test:key1")
+
+	if _, err := cache.Get("key1"); err != redis_cache.ErrCacheMiss {
+		t.Fatalf("Get() error = %v, want ErrCacheMiss", err)
+	}
+	// A second miss is not reported again.
+	cache.Get("key1")
+
+	if len(*events) != 1 || (*events)[0] != (redisEviction{"key1", "", cachepkg.ReasonExpired}) {
+		t.Errorf("OnEvict events = %v, want one expiry of key1", *events)
+	}
+}
+
+func TestRedisCache_OnEvictCleared(t *testing.T) {
+	cache, events := newHookedCache(t, 10)
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+
+	if err := cache.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll() error = %v", err)
+	}
+
+	var keys []string
+	for _, event := range *events {
+		if event.reason != cachepkg.ReasonCleared {
+			t.Errorf("event %v, want ReasonCleared", event)
+		}
+		keys = append(keys, event.key)
+	}
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "key1" || keys[1] != "key2" {
+		t.Errorf("cleared keys = %v, want [key1 key2]", keys)
+	}
+}
+
+func TestRedisBackend_OnEvictDecodesValues(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 1, redis_cache.WithPrefix("hooks-test:"))
+	backend := redis_cache.NewBackend(cache)
+	ctx := context.Background()
+	backend.DeleteAll(ctx)
+	defer backend.Close()
+	defer backend.DeleteAll(ctx)
+
+	var evicted interface{}
+	backend.OnEvict(func(key string, value interface{}, reason cachepkg.EvictionReason) {
+		evicted = value
+	})
+
+	backend.Set(ctx, "key1", map[string]interface{}{"name": "alice"}, 0)
+	backend.Set(ctx, "key2", "second", 0) // Evicts key1
+
+	value, ok := evicted.(map[string]interface{})
+	if !ok || value["name"] != "alice" {
+		t.Errorf("evicted value = %#v, want the decoded map", evicted)
+	}
+}
+
+func TestRedisCache_DeleteWithHooksReportsLaterErrors(t *testing.T) {
+	cache, _ := newHookedCache(t, 2)
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	// The GET of a missing key fails with redis.Nil first; the HDEL on a
+	// version index that is not a hash must still be reported.
+	client.Set(context.Background(), "redis_cache:hooks-test::version", "not a hash", 0)
+	if err := cache.Delete("missing"); err == nil {
+		t.Error("Delete() expected the HDEL error, got nil")
+	}
+	if _, err := cache.DeleteMulti([]string{"missing"}); err == nil {
+		t.Error("DeleteMulti() expected the HDEL error, got nil")
+	}
+}
@@ -0,0 +1,79 @@
+package redis_cache_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+func newLoaderCache(t *testing.T, opts ...redis_cache.Option) *redis_cache.Cache {
+	opts = append(opts, redis_cache.WithPrefix("loader-test:"))
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 10, opts...)
+	cache.DeleteAll()
+	t.Cleanup(func() {
+		cache.DeleteAll()
+		cache.Close()
+	})
+	return cache
+}
+
+func TestRedisCache_GetOrLoadCoalescesMisses(t *testing.T) {
+	cache := newLoaderCache(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := redis_cache.GetOrLoad(ctx, cache, "hot", loader); err != nil || value != 42 {
+				t.Errorf("expected 42, got %d, %v", value, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected one loader call, got %d", n)
+	}
+	if value, err := cache.Get("hot"); err != nil || value != 42 {
+		t.Errorf("expected 42 stored in Redis, got %d, %v", value, err)
+	}
+}
+
+func TestRedisCache_GetOrLoadNegativeTTL(t *testing.T) {
+	cache := newLoaderCache(t, redis_cache.WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	var calls int
+	notFound := func() (string, error) {
+		calls++
+		return "", fmt.Errorf("no row: %w", redis_cache.ErrCacheMiss)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := redis_cache.GetOrLoad(ctx, cache, "absent", notFound); err != redis_cache.ErrCacheMiss {
+			t.Fatalf("expected ErrCacheMiss, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one loader call, got %d", calls)
+	}
+	if ok, _ := cache.Exists("absent"); ok {
+		t.Error("expected nothing stored for a negative result")
+	}
+}
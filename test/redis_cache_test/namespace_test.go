@@ -0,0 +1,74 @@
+package redis_cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestRedisCache_PrefixesAreIsolated(t *testing.T) {
+	users := redis_cache.NewRedisCache("localhost:6379", "", 0, 2, redis_cache.WithPrefix("users:"))
+	orders := redis_cache.NewRedisCache("localhost:6379", "", 0, 2, redis_cache.WithPrefix("orders:"))
+	defer users.DeleteAll()
+	defer orders.DeleteAll()
+
+	users.Set("1", 1, redis_cache.StandardTTL)
+	users.Set("2", 2, redis_cache.StandardTTL)
+	orders.Set("1", 100, redis_cache.StandardTTL)
+	orders.Set("2", 200, redis_cache.StandardTTL)
+
+	// Same key, different namespaces
+	value, err := orders.Get("1")
+	if err != nil || value != 100 {
+		t.Errorf("orders.Get(1) = %v, %v, want 100, nil", value, err)
+	}
+
+	// Filling one cache must not evict keys from the other
+	users.Set("3", 3, redis_cache.StandardTTL)
+	keys, err := orders.GetAllKeys()
+	if err != nil {
+		t.Fatalf("GetAllKeys() error = %v, want nil", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("orders.GetAllKeys() got = %v, want 2 keys", keys)
+	}
+
+	// Deleting all users leaves orders alone
+	if err := users.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll() error = %v, want nil", err)
+	}
+	if keys, _ := users.GetAllKeys(); len(keys) != 0 {
+		t.Errorf("users.GetAllKeys() got = %v after DeleteAll(), want none", keys)
+	}
+	if _, err := orders.Get("2"); err != nil {
+		t.Errorf("orders.Get(2) error = %v after users.DeleteAll(), want nil", err)
+	}
+}
+
+func TestRedisCache_DeleteAllKeepsForeignKeys(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	client.Set(ctx, "foreign_key", "keep me", 0)
+	defer client.Del(ctx, "foreign_key")
+
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3, redis_cache.WithPrefix("scoped:"))
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+
+	keys, err := cache.GetAllKeys()
+	if err != nil {
+		t.Fatalf("GetAllKeys() error = %v, want nil", err)
+	}
+	if len(keys) != 1 || keys[0] != "key1" {
+		t.Errorf("GetAllKeys() got = %v, want [key1]", keys)
+	}
+
+	if err := cache.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll() error = %v, want nil", err)
+	}
+	if n, _ := client.Exists(ctx, "foreign_key").Result(); n != 1 {
+		t.Errorf("foreign_key was removed by DeleteAll()")
+	}
+}
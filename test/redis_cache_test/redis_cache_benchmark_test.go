@@ -1,10 +1,14 @@
 package redis_cache_test
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Devisree146/Go_project-library.git/redis_cache"
+	"github.com/go-redis/redis/v8"
 )
 
 const (
@@ -65,6 +69,91 @@ func BenchmarkRedisCache_GetAllKeys(b *testing.B) {
 	}
 }
 
+// Eviction benchmarks: Set on a full cache of growing size. With the sorted
+// set index the cost per Set stays flat, while the old scan + OBJECT IDLETIME
+// scan (reproduced in scanEviction below) grows with the keyspace.
+
+var evictionSizes = []int{100, 1000, 10000}
+
+func fillCache(b *testing.B, cache *redis_cache.Cache, size int) {
+	cache.DeleteAll()
+	for i := 0; i < size; i++ {
+		if err := cache.Set(benchmarkKey+strconv.Itoa(i), benchmarkValue, redis_cache.StandardTTL); err != nil {
+			b.Fatalf("Set() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkRedisCache_SetWithEviction(b *testing.B) {
+	for _, size := range evictionSizes {
+		b.Run(fmt.Sprintf("keys=%d", size), func(b *testing.B) {
+			cache := redis_cache.NewRedisCache("localhost:6379", "", 0, size)
+			fillCache(b, cache, size)
+			defer cache.DeleteAll()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				cache.Set(benchmarkKey+strconv.Itoa(size+i), benchmarkValue, redis_cache.StandardTTL)
+			}
+		})
+	}
+}
+
+// scanPrefix namespaces the keys of BenchmarkRedisCache_ScanEviction so the
+// scan sees only the cache's keys, not its bookkeeping or other tests' keys.
+const scanPrefix = "scan-benchmark:"
+
+func BenchmarkRedisCache_ScanEviction(b *testing.B) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	for _, size := range evictionSizes {
+		b.Run(fmt.Sprintf("keys=%d", size), func(b *testing.B) {
+			cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 0, redis_cache.WithPrefix(scanPrefix))
+			fillCache(b, cache, size)
+			defer cache.DeleteAll()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				client.Set(context.Background(), scanPrefix+benchmarkKey+strconv.Itoa(size+i), benchmarkValue, redis_cache.StandardTTL)
+				scanEviction(client, size)
+			}
+		})
+	}
+}
+
+// scanEviction is the eviction strategy the cache used before the sorted set
+// index: a listing of the keyspace plus one OBJECT IDLETIME round trip per
+// key. The listing is a SCAN of the cache's namespace, as the cache does.
+func scanEviction(client *redis.Client, maxSize int) {
+	ctx := context.Background()
+	var keys []string
+	iter := client.Scan(ctx, 0, scanPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if iter.Err() != nil || len(keys) <= maxSize {
+		return
+	}
+
+	var oldestKey string
+	var oldestTime time.Time
+	for _, key := range keys {
+		idle, err := client.ObjectIdleTime(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		lastAccessed := time.Now().Add(-idle)
+		if oldestTime.IsZero() || lastAccessed.Before(oldestTime) {
+			oldestTime = lastAccessed
+			oldestKey = key
+		}
+	}
+	if oldestKey != "" {
+		client.Del(ctx, oldestKey)
+	}
+}
+
 // Negative Benchmarking
 
 func BenchmarkRedisCache_Set_Failure(b *testing.B) {
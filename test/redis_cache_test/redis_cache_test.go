@@ -1,7 +1,9 @@
 package redis_cache_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/Devisree146/Go_project-library.git/redis_cache"
 )
@@ -98,3 +100,68 @@ func TestRedisCache_DeleteAll(t *testing.T) {
 		t.Errorf("DeleteAll() expected error, got nil")
 	}
 }
+
+func TestRedisCache_TTL(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
+	defer cache.DeleteAll()
+
+	cache.Set("ttl_key", 1, time.Minute)
+	ttl, err := cache.TTL("ttl_key")
+	if err != nil {
+		t.Fatalf("TTL() error = %v, want nil", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL() got = %v, want within (0, 1m]", ttl)
+	}
+
+	cache.Set("persistent_key", 1, 0)
+	if ttl, _ := cache.TTL("persistent_key"); ttl != -1 {
+		t.Errorf("TTL() got = %v for key without expiry, want -1", ttl)
+	}
+
+	// A TTL under a millisecond still expires the key
+	cache.Set("short_key", 1, 500*time.Microsecond)
+	time.Sleep(10 * time.Millisecond)
+	if _, err := cache.Get("short_key"); err != redis_cache.ErrCacheMiss {
+		t.Errorf("Get() error = %v for an expired sub-millisecond TTL, want ErrCacheMiss", err)
+	}
+
+	// Negative test case: TTL of a missing key
+	if _, err := cache.TTL("missing_key"); err != redis_cache.ErrCacheMiss {
+		t.Errorf("TTL() error = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestRedisBackend_DefaultTTL(t *testing.T) {
+	c := redis_cache.NewRedisCache("localhost:6379", "", 0, 10,
+		redis_cache.WithPrefix("default-ttl-test:"), redis_cache.WithDefaultTTL(time.Minute))
+	defer c.DeleteAll()
+	backend := redis_cache.NewBackend(c)
+	ctx := context.Background()
+
+	backend.Set(ctx, "set_key", 1, 0)
+	backend.IncrBy(ctx, "counter_key", 1)
+	backend.GetOrLoad(ctx, "loaded_key", func() (interface{}, error) { return 1, nil })
+
+	for _, key := range []string{"set_key", "counter_key", "loaded_key"} {
+		ttl, err := c.TTL(key)
+		if err != nil {
+			t.Fatalf("TTL(%q) error = %v, want nil", key, err)
+		}
+		if ttl <= 0 || ttl > time.Minute {
+			t.Errorf("TTL(%q) got = %v, want within (0, 1m]", key, ttl)
+		}
+	}
+}
+
+func TestRedisCache_Close(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 3)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+
+	// Negative test case: a closed cache cannot reach Redis
+	if err := cache.Set("key1", 1, redis_cache.StandardTTL); err == nil {
+		t.Errorf("Set() after Close() expected error, got nil")
+	}
+}
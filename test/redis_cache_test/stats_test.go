@@ -0,0 +1,40 @@
+package redis_cache_test
+
+import (
+	"testing"
+
+	"github.com/Devisree146/Go_project-library.git/redis_cache"
+)
+
+func TestRedisCache_Stats(t *testing.T) {
+	cache := redis_cache.NewRedisCache("localhost:6379", "", 0, 2, redis_cache.WithPrefix("stats-test:"))
+	cache.DeleteAll()
+	defer cache.Close()
+
+	cache.Set("key1", 1, redis_cache.StandardTTL)
+	cache.Set("key2", 2, redis_cache.StandardTTL)
+	cache.Get("key2")
+	cache.Get("missing")
+	cache.Set("key3", 3, redis_cache.StandardTTL) // Evicts key1
+	cache.Delete("key2")
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Sets != 3 || stats.Deletes != 1 {
+		t.Errorf("Stats() counters = %+v", stats)
+	}
+	if stats.Evictions.Capacity != 1 || stats.Evictions.Deleted != 1 {
+		t.Errorf("Stats() evictions = %+v, want 1 capacity and 1 deleted", stats.Evictions)
+	}
+	if stats.Entries != 1 {
+		t.Errorf("Stats() entries = %d, want 1", stats.Entries)
+	}
+
+	cache.DeleteAll()
+	stats, _ = cache.Stats()
+	if stats.Evictions.Cleared != 1 || stats.Entries != 0 {
+		t.Errorf("Stats() after DeleteAll = %+v, want 1 cleared and no entries", stats)
+	}
+}